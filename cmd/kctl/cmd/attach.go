@@ -0,0 +1,216 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/containerd/console"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+)
+
+// attachCmd represents the attach command
+var attachCmd = &cobra.Command{
+	Use:   "attach <id>",
+	Short: "Attach to the stdio of a running container",
+	Long: `Attach the local terminal to a container's process. A container created with
+--tty gets a fully interactive session, with ctrl-p ctrl-q detaching and
+leaving it running. Clients can detach and re-attach as often as they like.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		noStdin, _ := cmd.Flags().GetBool("no-stdin")
+		execID, _ := cmd.Flags().GetString("exec-id")
+		tty, _ := cmd.Flags().GetBool("tty")
+		id := args[0]
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		interactive := !noStdin
+		if execID == "" {
+			resp, err := client.Get(ctx, &containerTask.GetContainerRequest{Id: id})
+			if err != nil {
+				log.Fatalf("Failed to get container: %v", err)
+			}
+			tty = resp.Container.Terminal
+			interactive = interactive && (resp.Container.Stdin || tty)
+		}
+		stream, err := attachProcess(ctx, client, id, execID)
+		if err != nil {
+			log.Fatalf("Failed to attach: %v", err)
+		}
+		if err := streamProcess(ctx, client, stream, id, execID, interactive, tty); err != nil && !errors.Is(err, errDetached) {
+			log.Fatalf("Failed to stream container: %v", err)
+		}
+	},
+}
+
+// attachProcess opens an attach stream to a process and returns once the
+// shim acknowledged it
+func attachProcess(ctx context.Context, c containerTask.ContainersClient, id, execID string) (containerTask.Containers_AttachClient, error) {
+	stream, err := c.Attach(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&containerTask.AttachRequest{ContainerId: id, ExecId: execID}); err != nil {
+		return nil, err
+	}
+	if _, err := stream.Recv(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// errDetached is returned by streamProcess when the user detached with the
+// detach sequence, leaving the process running
+var errDetached = errors.New("detached")
+
+// detachSequence leaves an attached terminal session, ctrl-p ctrl-q as in docker
+var detachSequence = []byte{0x10, 0x11}
+
+// detachMatcher looks for the detach sequence in the input of a terminal,
+// holding back the bytes of a partial match until it completes or breaks
+type detachMatcher struct {
+	matched int
+}
+
+// feed returns the bytes of p to forward and whether the detach sequence was
+// completed, in which case the input after it is dropped
+func (m *detachMatcher) feed(p []byte) ([]byte, bool) {
+	var data []byte
+	for _, b := range p {
+		if m.matched > 0 && b != detachSequence[m.matched] {
+			// the bytes held back were input after all, b may start the
+			// sequence over
+			data = append(data, m.flush()...)
+		}
+		if b == detachSequence[m.matched] {
+			if m.matched++; m.matched == len(detachSequence) {
+				m.matched = 0
+				return data, true
+			}
+			continue
+		}
+		data = append(data, b)
+	}
+	return data, false
+}
+
+// flush returns the bytes held back and starts matching over
+func (m *detachMatcher) flush() []byte {
+	held := detachSequence[:m.matched]
+	m.matched = 0
+	return held
+}
+
+// streamProcess copies the local stdio to and from an attached process until
+// the process closes its output
+func streamProcess(ctx context.Context, c containerTask.ContainersClient, stream containerTask.Containers_AttachClient, id, execID string, interactive, tty bool) error {
+	if tty {
+		current := console.Current()
+		if err := current.SetRaw(); err != nil {
+			return fmt.Errorf("failed to put terminal into raw mode: %w", err)
+		}
+		defer current.Reset()
+
+		resize := func() {
+			size, err := current.Size()
+			if err != nil {
+				return
+			}
+			c.ResizePty(ctx, &containerTask.ResizePtyRequest{
+				ContainerId: id,
+				ExecId:      execID,
+				Width:       uint32(size.Width),
+				Height:      uint32(size.Height),
+			})
+		}
+		resize()
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, unix.SIGWINCH)
+		defer signal.Stop(winch)
+		go func() {
+			for range winch {
+				resize()
+			}
+		}()
+	}
+
+	detached := make(chan struct{})
+	if interactive {
+		go func() {
+			buf := make([]byte, 32*1024)
+			var matcher detachMatcher
+			for {
+				n, err := os.Stdin.Read(buf)
+				data, detach := buf[:n], false
+				if tty {
+					data, detach = matcher.feed(buf[:n])
+					if err != nil {
+						// a partial sequence at the end of the input was input
+						data = append(data, matcher.flush()...)
+					}
+				}
+				if len(data) > 0 {
+					if stream.Send(&containerTask.AttachRequest{Stdin: data}) != nil {
+						return
+					}
+				}
+				if detach {
+					close(detached)
+					return
+				}
+				if err != nil {
+					stream.Send(&containerTask.AttachRequest{CloseStdin: true})
+					stream.CloseSend()
+					return
+				}
+			}
+		}()
+	} else {
+		stream.CloseSend()
+	}
+
+	output := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				output <- nil
+				return
+			}
+			if err != nil {
+				output <- err
+				return
+			}
+			os.Stdout.Write(resp.Stdout)
+			os.Stderr.Write(resp.Stderr)
+		}
+	}()
+	select {
+	case err := <-output:
+		return err
+	case <-detached:
+		return errDetached
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+
+	attachCmd.Flags().Bool("no-stdin", false, "Do not forward local stdin to the process")
+	attachCmd.Flags().String("exec-id", "", "Attach to an exec process instead of the container's init")
+	attachCmd.Flags().BoolP("tty", "t", false, "The exec process runs on a terminal")
+}
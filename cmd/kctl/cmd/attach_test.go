@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetachMatcher(t *testing.T) {
+	tests := []struct {
+		name   string
+		reads  []string
+		want   string
+		detach bool
+	}{
+		{"plain input", []string{"ls\n"}, "ls\n", false},
+		{"sequence", []string{"ab\x10\x11cd"}, "ab", true},
+		{"split across reads", []string{"ab\x10", "\x11"}, "ab", true},
+		{"broken sequence", []string{"\x10x"}, "\x10x", false},
+		{"broken across reads", []string{"\x10", "x"}, "\x10x", false},
+		// the byte breaking the match may start it over
+		{"repeated ctrl-p", []string{"\x10\x10\x11"}, "\x10", true},
+		{"lone ctrl-q", []string{"\x11"}, "\x11", false},
+	}
+	for _, tt := range tests {
+		var m detachMatcher
+		var got []byte
+		detached := false
+		for _, r := range tt.reads {
+			data, done := m.feed([]byte(r))
+			got = append(got, data...)
+			if done {
+				detached = true
+				break
+			}
+		}
+		if detached != tt.detach || !bytes.Equal(got, []byte(tt.want)) {
+			t.Errorf("%s: forwarded %q, detached %v, want %q, %v", tt.name, got, detached, tt.want, tt.detach)
+		}
+	}
+}
+
+func TestDetachMatcherFlush(t *testing.T) {
+	var m detachMatcher
+	if data, done := m.feed([]byte("a\x10")); done || string(data) != "a" {
+		t.Fatalf("feed = %q, %v", data, done)
+	}
+	// at the end of input the held bytes are forwarded
+	if held := m.flush(); string(held) != "\x10" {
+		t.Errorf("flush = %q, want the held ctrl-p", held)
+	}
+	if held := m.flush(); len(held) != 0 {
+		t.Errorf("second flush = %q, want nothing", held)
+	}
+}
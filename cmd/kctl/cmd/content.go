@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// contentCmd represents the content command
+var contentCmd = &cobra.Command{
+	Use:   "content",
+	Short: "Manage the blobs in the content store",
+	Long: `Inspect and remove the blobs images are made of. kctl gc removes the ones
+nothing references any more.`,
+}
+
+// contentLsCmd represents the content ls command
+var contentLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List blobs in the content store",
+	Long: `List every blob in the content store with its size, when it was written and
+whether an image, container or pull in progress references it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		quiet, _ := cmd.Flags().GetBool("quiet")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		resp, err := client.ListContent(ctx, &containerTask.ListContentRequest{})
+		if err != nil {
+			log.Fatalf("Failed to list content: %v", err)
+		}
+		if quiet {
+			for _, blob := range resp.Blobs {
+				fmt.Println(blob.Digest)
+			}
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+		fmt.Fprintln(w, "DIGEST\tSIZE\tCREATED\tREFERENCED")
+		for _, blob := range resp.Blobs {
+			created := units.HumanDuration(time.Since(blob.CreatedAt.AsTime())) + " ago"
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", blob.Digest, units.HumanSize(float64(blob.Size)), created, blob.Referenced)
+		}
+		w.Flush()
+	},
+}
+
+// contentRmCmd represents the content rm command
+var contentRmCmd = &cobra.Command{
+	Use:   "rm <digest>...",
+	Short: "Remove blobs from the content store",
+	Long: `Remove the given blobs. A blob an image, a container or a pull in flight
+references is kept unless --force is given, containers cannot be created from
+an image missing blobs until it is pulled again.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		force, _ := cmd.Flags().GetBool("force")
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		failed := false
+		for _, dgst := range args {
+			if _, err := client.DeleteContent(ctx, &containerTask.DeleteContentRequest{Digest: dgst, Force: force}); err != nil {
+				log.Printf("Failed to remove %s: %v", dgst, err)
+				failed = true
+				continue
+			}
+			fmt.Println(dgst)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(contentCmd)
+	contentCmd.AddCommand(contentLsCmd)
+	contentCmd.AddCommand(contentRmCmd)
+
+	contentLsCmd.Flags().BoolP("quiet", "q", false, "Only print digests")
+	contentRmCmd.Flags().BoolP("force", "f", false, "Remove blobs even if they are referenced")
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"kettle/pkg/archive"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp <id>:<path> <local> | <local> <id>:<path>",
+	Short: "Copy files between a container and the local filesystem",
+	Long: `Copy files or directories into or out of a container, keeping ownership,
+permissions and symlinks. If the destination is an existing directory the
+source is copied into it, otherwise it is copied to the destination name.
+
+Local paths containing a colon must start with ./ or /.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		srcID, srcPath := splitCopyArg(args[0])
+		dstID, dstPath := splitCopyArg(args[1])
+		if (srcID == "") == (dstID == "") {
+			log.Fatalf("Exactly one of source and destination must be a container path")
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		if srcID != "" {
+			err = copyFromContainer(ctx, client, srcID, srcPath, dstPath)
+		} else {
+			err = copyToContainer(ctx, client, srcPath, dstID, dstPath)
+		}
+		if err != nil {
+			log.Fatalf("Failed to copy: %v", err)
+		}
+	},
+}
+
+// splitCopyArg splits id:path, returning an empty id for local paths
+func splitCopyArg(arg string) (id, path string) {
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") {
+		return "", arg
+	}
+	id, path, ok := strings.Cut(arg, ":")
+	if !ok || strings.Contains(id, "/") {
+		return "", arg
+	}
+	return id, path
+}
+
+func copyFromContainer(ctx context.Context, c containerTask.ContainersClient, id, path, local string) error {
+	dst, err := filepath.Abs(local)
+	if err != nil {
+		return err
+	}
+	stream, err := c.CopyFrom(ctx, &containerTask.CopyFromRequest{Id: id, Path: path})
+	if err != nil {
+		return err
+	}
+	return archive.CopyIn(&copyFromReader{stream: stream}, "/", dst)
+}
+
+func copyToContainer(ctx context.Context, c containerTask.ContainersClient, local, id, path string) error {
+	src, err := filepath.Abs(local)
+	if err != nil {
+		return err
+	}
+	stream, err := c.CopyTo(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&containerTask.CopyToRequest{Id: id, Path: path}); err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(archive.CopyOut(pw, "/", src))
+	}()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := pr.Read(buf)
+		if n > 0 {
+			if serr := stream.Send(&containerTask.CopyToRequest{Data: buf[:n]}); serr != nil {
+				if errors.Is(serr, io.EOF) {
+					// the daemon gave up, the real error comes with CloseAndRecv
+					break
+				}
+				return serr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+type copyFromReader struct {
+	stream containerTask.Containers_CopyFromClient
+	buf    []byte
+}
+
+func (r *copyFromReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = msg.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type TaskServiceImpl struct{}
@@ -44,11 +45,25 @@ to quickly create a Cobra application.`,
 
 		bundle, err := cmd.Flags().GetString("bundle")
 		if err != nil {
-			log.Fatalf("Failed to get id flag: %v", err)
+			log.Fatalf("Failed to get bundle flag: %v", err)
 		}
 		if bundle == "" {
-			log.Fatalf("Container ID is required")
+			log.Fatalf("Bundle path is required")
 		}
+		restart, err := cmd.Flags().GetString("restart")
+		if err != nil {
+			log.Fatalf("Failed to get restart flag: %v", err)
+		}
+		maxBackoff, err := cmd.Flags().GetDuration("max-backoff")
+		if err != nil {
+			log.Fatalf("Failed to get max-backoff flag: %v", err)
+		}
+		stableWindow, err := cmd.Flags().GetDuration("stable-window")
+		if err != nil {
+			log.Fatalf("Failed to get stable-window flag: %v", err)
+		}
+		tty, _ := cmd.Flags().GetBool("tty")
+		interactive, _ := cmd.Flags().GetBool("interactive")
 		clientContext, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 		testConnection()
@@ -59,14 +74,21 @@ to quickly create a Cobra application.`,
 		container := containerTask.Container{
 			ID:     id,
 			Bundle: bundle,
+			RestartPolicy: &containerTask.RestartPolicy{
+				Name:         restart,
+				MaxBackoff:   durationpb.New(maxBackoff),
+				StableWindow: durationpb.New(stableWindow),
+			},
+			Terminal: tty,
+			Stdin:    interactive,
 		}
 
 		req := containerTask.CreateContainerRequest{
 			Container: &container,
 		}
-		resp, _ := client.Create(clientContext, &req)
+		resp, err := client.Create(clientContext, &req)
 		if err != nil {
-			log.Fatalf("Failed to get id flag: %v", err)
+			log.Fatalf("Failed to create container: %v", err)
 		}
 		fmt.Println(resp)
 		return
@@ -114,6 +136,11 @@ func init() {
 	// Here you will define your flags and configuration settings.
 	createCmd.PersistentFlags().String("id", "", "container id")
 	createCmd.PersistentFlags().String("bundle", "", "bundle path")
+	createCmd.PersistentFlags().String("restart", "never", "restart policy: never, on-failure or always")
+	createCmd.PersistentFlags().Duration("max-backoff", 5*time.Minute, "upper bound for the delay between restarts")
+	createCmd.PersistentFlags().Duration("stable-window", 10*time.Minute, "uptime after which the restart backoff is reset")
+	createCmd.PersistentFlags().BoolP("tty", "t", false, "run the container's process on a terminal")
+	createCmd.PersistentFlags().BoolP("interactive", "i", false, "keep the container's stdin open for kctl attach")
 
 	// Cobra supports Persistent Flags which will work for this command
 	// and all subcommands, e.g.:
@@ -5,36 +5,46 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
 
 	"github.com/spf13/cobra"
 )
 
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
-	Use:   "delete",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Use:   "delete [flags] [<id>...]",
+	Short: "Delete containers",
+	Long: `Delete the given containers, or every container matching --selector, along
+with their shims, bundles, logs and state. Running containers are refused
+unless --force is given, which kills them first.
+
+The ID of every deleted container is printed; containers that could not be
+deleted are reported and make kctl delete fail once all were tried.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("delete called")
+		ctx := cmd.Context()
+		force, _ := cmd.Flags().GetBool("force")
+		selectors, _ := cmd.Flags().GetStringArray("selector")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		ids, err := selectContainers(ctx, client, args, selectors, "")
+		if err != nil {
+			log.Fatalf("Failed to select containers: %v", err)
+		}
+		forEachContainer(ids, "delete", func(id string) error {
+			_, err := client.Delete(ctx, &containerTask.DeleteContainerRequest{Id: id, Force: force})
+			return err
+		})
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(deleteCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// deleteCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// deleteCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	deleteCmd.Flags().BoolP("force", "f", false, "Kill running containers before deleting them")
+	deleteCmd.Flags().StringArrayP("selector", "l", nil, "Select containers by label (key=value)")
 }
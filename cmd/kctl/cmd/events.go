@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream the daemon's events",
+	Long: `Stream what happens to containers, their shims and images as JSON, one
+event per line, until interrupted.
+
+Event types are create, start, exit, oom, backoff, delete, shim-start,
+shim-exit and pull. --filter takes key=value pairs, key being type, container
+or an attribute of the event such as exec_id or image. An event has to match
+every key, any of the values given for the same key:
+
+  kctl events --filter type=exit --filter type=oom --filter container=web`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		filters, _ := cmd.Flags().GetStringArray("filter")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		stream, err := client.Subscribe(ctx, &containerTask.SubscribeRequest{Filters: filters})
+		if err != nil {
+			log.Fatalf("Failed to subscribe to events: %v", err)
+		}
+		for {
+			event, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				log.Fatalf("Failed to read events: %v", err)
+			}
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				log.Fatalf("Failed to encode event: %v", err)
+			}
+			fmt.Println(string(data))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().StringArrayP("filter", "f", nil, "only stream the events matching key=value, can be repeated")
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] <id> <command> [args...]",
+	Short: "Run a command in a running container",
+	Long: `Run an additional process in a running container. The process gets the
+container's environment, capabilities and security settings, with the
+given flags applied on top.
+
+kctl exec exits with the exit status of the process. With --tty, ctrl-p ctrl-q
+detaches and leaves the process running.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		tty, _ := cmd.Flags().GetBool("tty")
+		detach, _ := cmd.Flags().GetBool("detach")
+		env, _ := cmd.Flags().GetStringArray("env")
+		workdir, _ := cmd.Flags().GetString("workdir")
+		user, _ := cmd.Flags().GetString("user")
+		execID, _ := cmd.Flags().GetString("exec-id")
+		id := args[0]
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		resp, err := client.Exec(ctx, &containerTask.ExecRequest{
+			ContainerId: id,
+			ExecId:      execID,
+			Process: &containerTask.ProcessSpec{
+				Args:     args[1:],
+				Env:      env,
+				Cwd:      workdir,
+				User:     user,
+				Terminal: tty,
+			},
+			Stdin: interactive && !detach,
+		})
+		if err != nil {
+			log.Fatalf("Failed to exec: %v", err)
+		}
+		execID = resp.ExecId
+
+		if detach {
+			if _, err := client.Start(ctx, &containerTask.StartRequest{ContainerId: id, ExecId: execID}); err != nil {
+				log.Fatalf("Failed to start exec: %v", err)
+			}
+			fmt.Println(execID)
+			return
+		}
+
+		// attach before starting so that no output is missed
+		stream, err := attachProcess(ctx, client, id, execID)
+		if err != nil {
+			log.Fatalf("Failed to attach: %v", err)
+		}
+		if _, err := client.Start(ctx, &containerTask.StartRequest{ContainerId: id, ExecId: execID}); err != nil {
+			log.Fatalf("Failed to start exec: %v", err)
+		}
+		if err := streamProcess(ctx, client, stream, id, execID, interactive, tty); err != nil {
+			if errors.Is(err, errDetached) {
+				return
+			}
+			log.Fatalf("Failed to stream exec: %v", err)
+		}
+
+		status, err := client.Wait(ctx, &containerTask.WaitRequest{ContainerId: id, ExecId: execID})
+		if err != nil {
+			log.Fatalf("Failed to wait for exec: %v", err)
+		}
+		os.Exit(int(status.ExitStatus))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().BoolP("interactive", "i", false, "Keep stdin open and forward it to the process")
+	execCmd.Flags().BoolP("tty", "t", false, "Allocate a terminal for the process")
+	execCmd.Flags().BoolP("detach", "d", false, "Start the process in the background and print its exec ID")
+	execCmd.Flags().StringArrayP("env", "e", nil, "Set an environment variable (KEY=VALUE)")
+	execCmd.Flags().StringP("workdir", "w", "", "Working directory inside the container")
+	execCmd.Flags().StringP("user", "u", "", "User to run as, user[:group] by name or ID")
+	execCmd.Flags().String("exec-id", "", "ID for the process, generated if empty")
+	// everything after the command belongs to the command
+	execCmd.Flags().SetInterspersed(false)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove unreferenced content",
+	Long: `Remove the blobs in the content store that no image, container or pull in
+progress references, along with writes abandoned for more than a day. The
+digests of the removed blobs are printed with --verbose.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		resp, err := client.GarbageCollect(ctx, &containerTask.GarbageCollectRequest{})
+		if err != nil {
+			log.Fatalf("Failed to collect garbage: %v", err)
+		}
+		if verbose {
+			for _, dgst := range resp.Removed {
+				fmt.Println(dgst)
+			}
+		}
+		fmt.Printf("Removed %d blobs (%s) and %d abandoned writes in %s\n", len(resp.Removed),
+			units.HumanSize(float64(resp.Freed)), resp.AbortedIngests, resp.Duration.AsDuration())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().BoolP("verbose", "v", false, "Print the digests of the removed blobs")
+}
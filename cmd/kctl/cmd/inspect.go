@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <id>...",
+	Short: "Show the full metadata of containers as JSON",
+	Long: `Print a JSON array describing each container: its record in the metadata
+store, the live state reported by its shim and its OCI runtime spec. A
+container whose last run was OOM-killed has "oomKilled": true in its state,
+along with the memory limit in effect then. A container the daemon's CNI
+plugins set up has its network namespace and addresses under "network".`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+
+		failed := false
+		details := []map[string]json.RawMessage{}
+		for _, id := range args {
+			detail, err := inspectContainer(ctx, client, id)
+			if err != nil {
+				log.Printf("Failed to inspect %s: %v", id, err)
+				failed = true
+				continue
+			}
+			details = append(details, detail)
+		}
+		out, err := json.MarshalIndent(details, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to encode containers: %v", err)
+		}
+		fmt.Println(string(out))
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// inspectContainer gathers the container record, its state and its spec. The
+// state is left out when the shim cannot be reached.
+func inspectContainer(ctx context.Context, c containerTask.ContainersClient, id string) (map[string]json.RawMessage, error) {
+	resp, err := c.Get(ctx, &containerTask.GetContainerRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	// the spec is printed as the OCI JSON it is, not as an opaque Any
+	container := proto.Clone(resp.Container).(*containerTask.Container)
+	spec, err := containerSpec(container)
+	if err != nil {
+		return nil, err
+	}
+	container.Spec = nil
+
+	detail := map[string]json.RawMessage{"spec": spec}
+	if detail["container"], err = protojson.Marshal(container); err != nil {
+		return nil, err
+	}
+	if state, err := c.State(ctx, &containerTask.StateRequest{ContainerId: id}); err == nil {
+		if detail["state"], err = protojson.Marshal(state); err != nil {
+			return nil, err
+		}
+	}
+	return detail, nil
+}
+
+// containerSpec returns the OCI spec the daemon hands out with the container,
+// null when it has none
+func containerSpec(container *containerTask.Container) (json.RawMessage, error) {
+	if container.Spec == nil || len(container.Spec.Value) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	if !json.Valid(container.Spec.Value) {
+		return nil, fmt.Errorf("spec of type %s is not JSON", container.Spec.TypeUrl)
+	}
+	return container.Spec.Value, nil
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+}
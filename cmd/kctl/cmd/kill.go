@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+
+	"github.com/moby/sys/signal"
+	"github.com/spf13/cobra"
+)
+
+// killCmd represents the kill command
+var killCmd = &cobra.Command{
+	Use:   "kill [flags] <id>",
+	Short: "Send a signal to a container",
+	Long: `Send a signal to a container's init process, every process in it with --all,
+or to one of its exec processes with --exec-id. Signals are given by name
+(HUP, SIGHUP) or number.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		name, _ := cmd.Flags().GetString("signal")
+		all, _ := cmd.Flags().GetBool("all")
+		execID, _ := cmd.Flags().GetString("exec-id")
+		sig, err := signal.ParseSignal(name)
+		if err != nil {
+			log.Fatalf("Invalid signal: %v", err)
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		_, err = client.Kill(ctx, &containerTask.KillRequest{
+			ContainerId: args[0],
+			ExecId:      execID,
+			Signal:      uint32(sig),
+			All:         all,
+		})
+		if err != nil {
+			log.Fatalf("Failed to kill %s: %v", args[0], err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(killCmd)
+
+	killCmd.Flags().StringP("signal", "s", "KILL", "Signal to send")
+	killCmd.Flags().BoolP("all", "a", false, "Signal every process in the container")
+	killCmd.Flags().String("exec-id", "", "Signal an exec process instead of the container's init")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs <id>",
+	Short: "Print the output of a container",
+	Long: `Print what a container wrote to stdout and stderr.
+
+--since takes either a duration relative to now (10m) or an RFC3339 timestamp.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		follow, _ := cmd.Flags().GetBool("follow")
+		timestamps, _ := cmd.Flags().GetBool("timestamps")
+		tail, _ := cmd.Flags().GetInt64("tail")
+		since, _ := cmd.Flags().GetString("since")
+
+		req := containerTask.LogsRequest{
+			Id:     args[0],
+			Follow: follow,
+		}
+		if tail >= 0 {
+			req.Tail = &tail
+		}
+		if since != "" {
+			t, err := parseSince(since)
+			if err != nil {
+				log.Fatalf("Invalid since value: %v", err)
+			}
+			req.Since = timestamppb.New(t)
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		stream, err := client.Logs(ctx, &req)
+		if err != nil {
+			log.Fatalf("Failed to get logs: %v", err)
+		}
+		// a line split into partial entries is printed with the timestamp of its first part
+		var lineStart *timestamppb.Timestamp
+		for {
+			entry, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				log.Fatalf("Failed to read logs: %v", err)
+			}
+			out := os.Stdout
+			if entry.Stream == "stderr" {
+				out = os.Stderr
+			}
+			if timestamps && lineStart == nil {
+				fmt.Fprintf(out, "%s ", entry.Timestamp.AsTime().Format(time.RFC3339Nano))
+			}
+			lineStart = entry.Timestamp
+			out.Write(entry.Line)
+			if !entry.Partial {
+				out.Write([]byte{'\n'})
+				lineStart = nil
+			}
+		}
+	},
+}
+
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().BoolP("follow", "f", false, "keep streaming new output until the container exits")
+	logsCmd.Flags().Int64("tail", -1, "number of lines to show from the end, all when negative")
+	logsCmd.Flags().String("since", "", "only show output since a duration ago or an RFC3339 timestamp")
+	logsCmd.Flags().BoolP("timestamps", "t", false, "prefix every line with its timestamp")
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause [flags] [<id>...]",
+	Short: "Freeze all processes of containers",
+	Long: `Freeze the processes of the given containers, or of every container matching
+--selector that is running, using the cgroup freezer. kctl unpause thaws
+them again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		selectors, _ := cmd.Flags().GetStringArray("selector")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		ids, err := selectContainers(ctx, client, args, selectors, "running")
+		if err != nil {
+			log.Fatalf("Failed to select containers: %v", err)
+		}
+		forEachContainer(ids, "pause", func(id string) error {
+			_, err := client.Pause(ctx, &containerTask.PauseRequest{ContainerId: id})
+			return err
+		})
+	},
+}
+
+// selectContainers returns the given IDs followed by the IDs of all containers
+// matching the key=value selectors, if any were given. Selected containers are
+// limited to those in status unless it is empty, as their shims report it: the
+// status the daemon recorded may be behind.
+func selectContainers(ctx context.Context, c containerTask.ContainersClient, ids, selectors []string, status string) ([]string, error) {
+	if len(ids) == 0 && len(selectors) == 0 {
+		return nil, fmt.Errorf("no container IDs or selectors given")
+	}
+	if len(selectors) == 0 {
+		return ids, nil
+	}
+	labels, err := parseLabels(selectors)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.List(ctx, &containerTask.ListContainersRequest{Labels: labels})
+	if err != nil {
+		return nil, err
+	}
+	for _, container := range resp.Containers {
+		if status != "" {
+			// a container whose state cannot be read is in none
+			state, err := c.State(ctx, &containerTask.StateRequest{ContainerId: container.ID})
+			if err != nil || state.Status != status {
+				continue
+			}
+		}
+		ids = append(ids, container.ID)
+	}
+	return ids, nil
+}
+
+// parseLabels turns key=value pairs into a label map
+func parseLabels(pairs []string) (map[string]string, error) {
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", pair)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// forEachContainer runs fn for every ID, printing the IDs it succeeded for and
+// failing once all were tried if any of them failed
+func forEachContainer(ids []string, action string, fn func(id string) error) {
+	failed := false
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			log.Printf("Failed to %s %s: %v", action, id, err)
+			failed = true
+			continue
+		}
+		fmt.Println(id)
+	}
+	if failed {
+		log.Fatalf("Failed to %s some containers", action)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+
+	pauseCmd.Flags().StringArrayP("selector", "l", nil, "Select containers by label (key=value)")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+// stateClient lists containers and reports the state of their tasks from
+// fixed tables
+type stateClient struct {
+	containerTask.ContainersClient
+	stored map[string]string
+	states map[string]string
+}
+
+func (c *stateClient) List(ctx context.Context, req *containerTask.ListContainersRequest, opts ...grpc.CallOption) (*containerTask.ListContainersResponse, error) {
+	var resp containerTask.ListContainersResponse
+	for _, id := range []string{"a", "b", "c"} {
+		resp.Containers = append(resp.Containers, &containerTask.Container{ID: id, Status: c.stored[id]})
+	}
+	return &resp, nil
+}
+
+func (c *stateClient) State(ctx context.Context, req *containerTask.StateRequest, opts ...grpc.CallOption) (*containerTask.StateResponse, error) {
+	state, ok := c.states[req.ContainerId]
+	if !ok {
+		return nil, fmt.Errorf("no shim for %s", req.ContainerId)
+	}
+	return &containerTask.StateResponse{ContainerId: req.ContainerId, Status: state}, nil
+}
+
+func TestSelectContainers(t *testing.T) {
+	// the records all say running, the shims know better
+	c := &stateClient{
+		stored: map[string]string{"a": "running", "b": "running", "c": "running"},
+		states: map[string]string{"a": "running", "b": "paused"},
+	}
+	selectors := []string{"app=web"}
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{"running", []string{"a"}},
+		{"paused", []string{"b"}},
+		{"", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		ids, err := selectContainers(context.Background(), c, nil, selectors, tt.status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("selected in status %q: %v, want %v", tt.status, ids, tt.want)
+		}
+	}
+
+	// IDs given by name are taken as they are
+	ids, err := selectContainers(context.Background(), c, []string{"c"}, nil, "running")
+	if err != nil || !slices.Equal(ids, []string{"c"}) {
+		t.Errorf("selectContainers(c) = %v, %v", ids, err)
+	}
+	if _, err := selectContainers(context.Background(), c, nil, nil, "running"); err == nil {
+		t.Error("selectContainers without IDs or selectors succeeded")
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// psEntry is one row of kctl ps, also what --format templates are executed on
+type psEntry struct {
+	ID        string            `json:"id"`
+	Image     string            `json:"image"`
+	Status    string            `json:"status"`
+	Pid       uint32            `json:"pid"`
+	Uptime    string            `json:"uptime"`
+	Restarts  uint32            `json:"restarts"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// psCmd represents the ps command
+var psCmd = &cobra.Command{
+	Use:   "ps [flags]",
+	Short: "List containers",
+	Long: `List running and paused containers, or all of them with --all.
+
+Containers can be filtered with --filter label=key=value and
+--filter status=<status>, given as often as needed. --format prints a table
+(the default), JSON, or runs a Go template for every container, for example
+--format '{{.ID}} {{.Status}}'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		all, _ := cmd.Flags().GetBool("all")
+		filters, _ := cmd.Flags().GetStringArray("filter")
+		format, _ := cmd.Flags().GetString("format")
+
+		var labels []string
+		var statuses []string
+		for _, filter := range filters {
+			key, value, ok := strings.Cut(filter, "=")
+			switch {
+			case !ok:
+				log.Fatalf("Invalid filter %q, expected key=value", filter)
+			case key == "label":
+				labels = append(labels, value)
+			case key == "status":
+				statuses = append(statuses, value)
+			default:
+				log.Fatalf("Unknown filter %q, expected label or status", key)
+			}
+		}
+		selector, err := parseLabels(labels)
+		if err != nil {
+			log.Fatalf("Invalid filter: %v", err)
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		resp, err := client.List(ctx, &containerTask.ListContainersRequest{Labels: selector})
+		if err != nil {
+			log.Fatalf("Failed to list containers: %v", err)
+		}
+
+		entries := []psEntry{}
+		for _, container := range resp.Containers {
+			entry := listEntry(ctx, client, container)
+			switch {
+			case len(statuses) > 0:
+				if !slices.Contains(statuses, entry.Status) {
+					continue
+				}
+			case !all:
+				if entry.Status != "running" && entry.Status != "paused" {
+					continue
+				}
+			}
+			entries = append(entries, entry)
+		}
+		slices.SortFunc(entries, func(a, b psEntry) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+
+		if err := printEntries(entries, format); err != nil {
+			log.Fatalf("Failed to print containers: %v", err)
+		}
+	},
+}
+
+// listEntry describes a container, asking its shim for the live state. The
+// recorded status is used when the shim cannot be reached.
+func listEntry(ctx context.Context, c containerTask.ContainersClient, container *containerTask.Container) psEntry {
+	entry := psEntry{
+		ID:        container.ID,
+		Image:     container.Image,
+		Status:    container.Status,
+		Uptime:    "-",
+		Labels:    container.Labels,
+		CreatedAt: container.CreatedAt.AsTime(),
+	}
+	if entry.Image == "" {
+		entry.Image = "-"
+	}
+	state, err := c.State(ctx, &containerTask.StateRequest{ContainerId: container.ID})
+	if err != nil {
+		return entry
+	}
+	entry.Status = state.Status
+	if state.Status == "stopped" {
+		entry.Status = "exited"
+	}
+	entry.Pid = state.Pid
+	entry.Restarts = state.RestartCount
+	if (entry.Status == "running" || entry.Status == "paused") && state.StartedAt != nil {
+		entry.Uptime = units.HumanDuration(time.Since(state.StartedAt.AsTime()))
+	}
+	return entry
+}
+
+// printEntries writes entries to stdout as a table, JSON or through a template
+func printEntries(entries []psEntry, format string) error {
+	switch format {
+	case "", "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+		fmt.Fprintln(w, "CONTAINER ID\tIMAGE\tSTATUS\tPID\tUPTIME\tRESTARTS\tLABELS")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%s\n", e.ID, e.Image, e.Status, e.Pid, e.Uptime, e.Restarts, formatLabels(e.Labels))
+		}
+		return w.Flush()
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+	for _, e := range entries {
+		if err := tmpl.Execute(os.Stdout, e); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func init() {
+	rootCmd.AddCommand(psCmd)
+
+	psCmd.Flags().BoolP("all", "a", false, "Show all containers, not just running and paused ones")
+	psCmd.Flags().StringArrayP("filter", "f", nil, "Filter containers (label=key=value or status=<status>)")
+	psCmd.Flags().String("format", "table", "Output format: table, json or a Go template")
+}
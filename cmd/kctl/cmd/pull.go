@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"kettle/pkg/image"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// pullCmd represents the pull command
+var pullCmd = &cobra.Command{
+	Use:   "pull <ref>",
+	Short: "Pull an image",
+	Long: `Fetch an image for the platform of this host into the daemon's content
+store. ref is a registry reference such as alpine or localhost:5000/app:v1,
+where registries on localhost are spoken to over plain HTTP, or an OCI image
+layout directory written as oci:<path>[:<tag>], a relative path being taken
+from the current directory.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		ref, err := image.AbsRef(args[0])
+		if err != nil {
+			log.Fatalf("Invalid image reference %s: %v", args[0], err)
+		}
+		resp, err := client.Pull(ctx, &containerTask.PullRequest{Ref: ref})
+		if err != nil {
+			log.Fatalf("Failed to pull %s: %v", args[0], err)
+		}
+		fmt.Printf("%s: %s\n", resp.Image.Name, resp.Image.Digest)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pullCmd)
+}
@@ -4,36 +4,231 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"kettle/pkg/image"
+	"kettle/pkg/oci"
+	"log"
+	"os"
+	"strings"
 
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/spf13/cobra"
 )
 
 // runCmd represents the run command
 var runCmd = &cobra.Command{
-	Use:   "run",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Use:   "run [flags] <id> [command [args...]]",
+	Short: "Create and start a container, attaching to it",
+	Long: `Create a container from an image or a bundle and start it, running the given
+command. Without one the image's entrypoint and command run, or sh for a
+bundle. Images not pulled yet are pulled first. The OCI spec is built from the
+flags and sent along with the container.
+
+Unless --detach is given the local terminal is attached to the container until
+it exits, and kctl run exits with the container's exit status. With --tty,
+ctrl-p ctrl-q detaches and leaves it running, kctl attach reconnects. --rm
+deletes the container once it has exited.`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("run called")
+		ctx := cmd.Context()
+		bundle, _ := cmd.Flags().GetString("bundle")
+		imageRef, _ := cmd.Flags().GetString("image")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		tty, _ := cmd.Flags().GetBool("tty")
+		detach, _ := cmd.Flags().GetBool("detach")
+		remove, _ := cmd.Flags().GetBool("rm")
+		restart, _ := cmd.Flags().GetString("restart")
+		labelPairs, _ := cmd.Flags().GetStringArray("label")
+		id := args[0]
+		if bundle == "" && imageRef == "" {
+			log.Fatalf("An image or a bundle path is required")
+		}
+		imageRef, err := image.AbsRef(imageRef)
+		if err != nil {
+			log.Fatalf("Invalid image reference: %v", err)
+		}
+		if remove && detach {
+			log.Fatalf("--rm cannot be combined with --detach")
+		}
+		if remove && restart != "never" {
+			log.Fatalf("--rm cannot be combined with a restart policy")
+		}
+
+		spec, err := runSpec(cmd, id, imageRef != "", args[1:])
+		if err != nil {
+			log.Fatalf("Invalid container spec: %v", err)
+		}
+		specAny, err := typeurl.MarshalAnyToProto(spec)
+		if err != nil {
+			log.Fatalf("Failed to encode container spec: %v", err)
+		}
+		labels, err := parseLabels(labelPairs)
+		if err != nil {
+			log.Fatalf("Invalid label: %v", err)
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		_, err = client.Create(ctx, &containerTask.CreateContainerRequest{
+			Container: &containerTask.Container{
+				ID:            id,
+				Bundle:        bundle,
+				Image:         imageRef,
+				Labels:        labels,
+				Spec:          specAny,
+				RestartPolicy: &containerTask.RestartPolicy{Name: restart},
+				Terminal:      tty,
+				Stdin:         interactive,
+			},
+		})
+		if err != nil {
+			log.Fatalf("Failed to create container: %v", err)
+		}
+
+		var stream containerTask.Containers_AttachClient
+		if !detach {
+			// attach before starting so that no output is missed
+			if stream, err = attachProcess(ctx, client, id, ""); err != nil {
+				removeContainer(ctx, client, id, remove)
+				log.Fatalf("Failed to attach: %v", err)
+			}
+		}
+		if _, err := client.Start(ctx, &containerTask.StartRequest{ContainerId: id}); err != nil {
+			removeContainer(ctx, client, id, remove)
+			log.Fatalf("Failed to start container: %v", err)
+		}
+		if detach {
+			fmt.Println(id)
+			return
+		}
+		if err := streamProcess(ctx, client, stream, id, "", interactive, tty); err != nil {
+			if errors.Is(err, errDetached) {
+				return
+			}
+			log.Fatalf("Failed to stream container: %v", err)
+		}
+		status, err := client.Wait(ctx, &containerTask.WaitRequest{ContainerId: id})
+		if err != nil {
+			log.Fatalf("Failed to wait for container: %v", err)
+		}
+		removeContainer(ctx, client, id, remove)
+		os.Exit(int(status.ExitStatus))
 	},
 }
 
-func init() {
-	rootCmd.AddCommand(runCmd)
+// runSpec builds the container's OCI spec from the run flags. For an image the
+// command, working directory and environment are left for the daemon to take
+// from the image config.
+func runSpec(cmd *cobra.Command, id string, fromImage bool, command []string) (*specs.Spec, error) {
+	tty, _ := cmd.Flags().GetBool("tty")
+	env, _ := cmd.Flags().GetStringArray("env")
+	mountValues, _ := cmd.Flags().GetStringArray("mount")
+	user, _ := cmd.Flags().GetString("user")
+	workdir, _ := cmd.Flags().GetString("workdir")
+	readonly, _ := cmd.Flags().GetBool("read-only")
 
-	// Here you will define your flags and configuration settings.
+	opts := []oci.SpecOpts{oci.WithHostname(id), oci.WithTTY(tty), oci.WithRootfsReadonly(readonly)}
+	if fromImage {
+		opts = append(opts, oci.WithProcessArgs(), oci.WithProcessCwd(""), oci.WithProcessEnv(nil))
+	}
+	opts = append(opts, oci.WithEnv(env))
+	if workdir != "" {
+		opts = append(opts, oci.WithProcessCwd(workdir))
+	}
+	if len(command) > 0 {
+		opts = append(opts, oci.WithProcessArgs(command...))
+	}
+	var mounts []specs.Mount
+	for _, value := range mountValues {
+		m, err := parseMount(value)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+	opts = append(opts, oci.WithMounts(mounts))
+	resources, err := resourceOpts(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, resources...)
+	if user != "" {
+		opts = append(opts, oci.WithUser(user))
+	}
+	return oci.GenerateSpec(opts...)
+}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// runCmd.PersistentFlags().String("foo", "", "A help for foo")
+// parseMount parses a docker style mount:
+// type=bind,source=/src,target=/dst[,readonly][,options=a:b]
+func parseMount(value string) (specs.Mount, error) {
+	m := specs.Mount{Type: "bind"}
+	readonly := false
+	for _, field := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(field, "=")
+		switch key {
+		case "type":
+			m.Type = val
+		case "source", "src":
+			m.Source = val
+		case "target", "destination", "dst":
+			m.Destination = val
+		case "readonly", "ro":
+			readonly = val == "" || val == "true" || val == "1"
+		case "options":
+			m.Options = append(m.Options, strings.Split(val, ":")...)
+		default:
+			return m, fmt.Errorf("invalid mount %q: unknown field %q", value, key)
+		}
+	}
+	if m.Destination == "" {
+		return m, fmt.Errorf("invalid mount %q: target is required", value)
+	}
+	if m.Type == "bind" {
+		if m.Source == "" {
+			return m, fmt.Errorf("invalid mount %q: bind mounts need a source", value)
+		}
+		m.Options = append(m.Options, "rbind")
+	}
+	if readonly {
+		m.Options = append(m.Options, "ro")
+	}
+	return m, nil
+}
+
+// removeContainer deletes the container if --rm asked for it
+func removeContainer(ctx context.Context, c containerTask.ContainersClient, id string, remove bool) {
+	if !remove {
+		return
+	}
+	if _, err := c.Delete(ctx, &containerTask.DeleteContainerRequest{Id: id}); err != nil {
+		log.Printf("Failed to remove %s: %v", id, err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// runCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	runCmd.Flags().String("bundle", "", "Bundle path, one in the daemon's state directory for images if empty")
+	runCmd.Flags().String("image", "", "Image to create the container from")
+	runCmd.Flags().BoolP("interactive", "i", false, "Keep stdin open and forward it to the container")
+	runCmd.Flags().BoolP("tty", "t", false, "Allocate a terminal for the container")
+	runCmd.Flags().BoolP("detach", "d", false, "Start the container in the background and print its ID")
+	runCmd.Flags().Bool("rm", false, "Delete the container when it exits")
+	runCmd.Flags().StringArrayP("env", "e", nil, "Set an environment variable (KEY=VALUE)")
+	runCmd.Flags().StringArray("mount", nil, "Add a mount (type=bind,source=/src,target=/dst[,readonly])")
+	addResourceFlags(runCmd.Flags())
+	runCmd.Flags().String("restart", "never", "Restart policy: never, on-failure or always")
+	runCmd.Flags().StringArrayP("label", "l", nil, "Set a label on the container (key=value)")
+	runCmd.Flags().StringP("user", "u", "", "User to run as (uid or uid:gid)")
+	runCmd.Flags().StringP("workdir", "w", "", "Working directory inside the container")
+	runCmd.Flags().Bool("read-only", false, "Mount the container's root filesystem read-only")
+	// everything after the command belongs to the command
+	runCmd.Flags().SetInterspersed(false)
 }
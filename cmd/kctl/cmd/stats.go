@@ -0,0 +1,186 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// statsInterval is how often kctl stats samples, CPU usage is averaged over it
+const statsInterval = time.Second
+
+// statsEntry is one row of kctl stats
+type statsEntry struct {
+	ID               string  `json:"id"`
+	CPUPercent       float64 `json:"cpu_percent"`
+	ThrottledPeriods uint64  `json:"throttled_periods"`
+	MemoryUsage      uint64  `json:"memory_usage"`
+	MemoryLimit      uint64  `json:"memory_limit"`
+	MemoryPercent    float64 `json:"memory_percent"`
+	MemoryPeak       uint64  `json:"memory_peak"`
+	OOMKills         uint64  `json:"oom_kills"`
+	NetRx            uint64  `json:"net_rx_bytes"`
+	NetTx            uint64  `json:"net_tx_bytes"`
+	BlockRead        uint64  `json:"block_read_bytes"`
+	BlockWrite       uint64  `json:"block_write_bytes"`
+	Pids             uint64  `json:"pids"`
+	PidsLimit        uint64  `json:"pids_limit"`
+}
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [flags] [id...]",
+	Short: "Show what containers consume",
+	Long: `Show CPU, memory, network, block IO and pids usage of the given containers, or
+of all running ones, refreshed every second until interrupted. CPU usage is a
+percentage of one CPU, averaged since the previous refresh.
+
+--no-stream prints a single sample and exits, --format json prints JSON instead
+of the table, one array per sample:
+
+	kctl stats --no-stream --format json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		noStream, _ := cmd.Flags().GetBool("no-stream")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "table" && format != "json" {
+			log.Fatalf("Invalid format %q, expected table or json", format)
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		previous := map[string]*containerTask.StatsResponse{}
+		// the first sample only gives the CPU usage something to compare to
+		sampleStats(ctx, client, args, previous)
+		for {
+			time.Sleep(statsInterval)
+			entries := sampleStats(ctx, client, args, previous)
+			if !noStream && format == "table" {
+				// clear the screen and start over at the top, like top does
+				fmt.Print("\033[2J\033[H")
+			}
+			if err := printStats(entries, format); err != nil {
+				log.Fatalf("Failed to print stats: %v", err)
+			}
+			if noStream {
+				return
+			}
+		}
+	},
+}
+
+// sampleStats reads the stats of the containers named by ids, all running
+// ones without any, and turns them into entries. previous holds the last
+// sample of every container and is updated.
+func sampleStats(ctx context.Context, c containerTask.ContainersClient, ids []string, previous map[string]*containerTask.StatsResponse) []statsEntry {
+	explicit := len(ids) > 0
+	if !explicit {
+		resp, err := c.List(ctx, &containerTask.ListContainersRequest{})
+		if err != nil {
+			log.Fatalf("Failed to list containers: %v", err)
+		}
+		for _, container := range resp.Containers {
+			if container.Status == "running" || container.Status == "paused" {
+				ids = append(ids, container.ID)
+			}
+		}
+	}
+	entries := []statsEntry{}
+	for _, id := range ids {
+		stats, err := c.Stats(ctx, &containerTask.StatsRequest{ContainerId: id})
+		if err != nil {
+			// containers that stopped since they were listed drop out quietly
+			if explicit {
+				log.Printf("Failed to get stats of %s: %v", id, err)
+			}
+			delete(previous, id)
+			continue
+		}
+		entries = append(entries, newStatsEntry(stats, previous[id]))
+		previous[id] = stats
+	}
+	slices.SortFunc(entries, func(a, b statsEntry) int { return strings.Compare(a.ID, b.ID) })
+	return entries
+}
+
+// newStatsEntry summarizes stats, the CPU usage relative to prev if there is one
+func newStatsEntry(stats, prev *containerTask.StatsResponse) statsEntry {
+	entry := statsEntry{ID: stats.Id}
+	if cpu := stats.Cpu; cpu != nil {
+		entry.ThrottledPeriods = cpu.NrThrottled
+		if prev != nil && prev.Cpu != nil {
+			elapsed := stats.ReadAt.AsTime().Sub(prev.ReadAt.AsTime()).Microseconds()
+			if elapsed > 0 && cpu.UsageUsec >= prev.Cpu.UsageUsec {
+				entry.CPUPercent = float64(cpu.UsageUsec-prev.Cpu.UsageUsec) / float64(elapsed) * 100
+			}
+		}
+	}
+	if mem := stats.Memory; mem != nil {
+		entry.MemoryUsage = mem.Usage
+		entry.MemoryLimit = mem.Limit
+		entry.MemoryPeak = mem.Peak
+		entry.OOMKills = mem.OomKillEvents
+		if mem.Limit > 0 {
+			entry.MemoryPercent = float64(mem.Usage) / float64(mem.Limit) * 100
+		}
+	}
+	for _, n := range stats.Networks {
+		entry.NetRx += n.RxBytes
+		entry.NetTx += n.TxBytes
+	}
+	if io := stats.Io; io != nil {
+		entry.BlockRead = io.ReadBytes
+		entry.BlockWrite = io.WriteBytes
+	}
+	if pids := stats.Pids; pids != nil {
+		entry.Pids = pids.Current
+		entry.PidsLimit = pids.Limit
+	}
+	return entry
+}
+
+// printStats writes entries to stdout as a table or JSON
+func printStats(entries []statsEntry, format string) error {
+	if format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(entries)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER ID\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS")
+	for _, e := range entries {
+		limit, memPercent := "-", "-"
+		if e.MemoryLimit > 0 {
+			limit = units.BytesSize(float64(e.MemoryLimit))
+			memPercent = fmt.Sprintf("%.2f%%", e.MemoryPercent)
+		}
+		fmt.Fprintf(w, "%s\t%.2f%%\t%s / %s\t%s\t%s / %s\t%s / %s\t%d\n",
+			e.ID, e.CPUPercent,
+			units.BytesSize(float64(e.MemoryUsage)), limit, memPercent,
+			units.HumanSize(float64(e.NetRx)), units.HumanSize(float64(e.NetTx)),
+			units.HumanSize(float64(e.BlockRead)), units.HumanSize(float64(e.BlockWrite)),
+			e.Pids)
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().Bool("no-stream", false, "Print a single sample instead of refreshing")
+	statsCmd.Flags().String("format", "table", "Output format: table or json")
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status <id>",
+	Short: "Show restart and crash-loop state of a container",
+	Long: `Show how often the shim restarted a container, how it last exited, whether
+the OOM killer was involved, and whether it is currently held back in
+CrashLoopBackOff.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		clientContext, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
+		client, err := client.GetGRPCTaskClient(clientContext)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		resp, err := client.RestartStatus(clientContext, &containerTask.RestartStatusRequest{ContainerId: args[0]})
+		if err != nil {
+			log.Fatalf("Failed to get restart status: %v", err)
+		}
+
+		state := "Running"
+		switch {
+		case resp.Reason != "":
+			state = fmt.Sprintf("%s (back-off %s, next restart in %s)", resp.Reason,
+				resp.Backoff.AsDuration(), time.Until(resp.NextRestartAt.AsTime()).Round(time.Second))
+		case resp.Pid == 0 && resp.LastExitAt != nil:
+			state = "Exited"
+		}
+		fmt.Printf("ID:\t\t%s\n", resp.ContainerId)
+		fmt.Printf("State:\t\t%s\n", state)
+		fmt.Printf("Pid:\t\t%d\n", resp.Pid)
+		fmt.Printf("Restarts:\t%d\n", resp.RestartCount)
+		if resp.LastExitAt != nil {
+			reason := ""
+			if resp.LastExitReason != "" {
+				reason = " (" + resp.LastExitReason + ")"
+			}
+			fmt.Printf("Last exit:\t%d%s at %s\n", resp.LastExitStatus, reason, resp.LastExitAt.AsTime().Local().Format(time.RFC3339))
+		}
+		if resp.OomKills > 0 {
+			fmt.Printf("OOM kills:\t%d\n", resp.OomKills)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop [flags] <id>...",
+	Short: "Stop running containers",
+	Long: `Send each container its stop signal, SIGTERM unless its image says otherwise,
+and kill it if it has not exited after --time seconds. A stopped container is
+not restarted by its restart policy.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		timeout, _ := cmd.Flags().GetInt("time")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		forEachContainer(args, "stop", func(id string) error {
+			_, err := client.Stop(ctx, &containerTask.StopRequest{
+				ContainerId: id,
+				Timeout:     durationpb.New(time.Duration(timeout) * time.Second),
+			})
+			return err
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+
+	stopCmd.Flags().IntP("time", "t", 10, "Seconds to wait for the container to exit before killing it")
+}
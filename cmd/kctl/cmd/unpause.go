@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// unpauseCmd represents the unpause command
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause [flags] [<id>...]",
+	Short: "Thaw paused containers",
+	Long: `Resume the processes of the given containers, or of every container matching
+--selector that is paused, after kctl pause froze them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		selectors, _ := cmd.Flags().GetStringArray("selector")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		ids, err := selectContainers(ctx, client, args, selectors, "paused")
+		if err != nil {
+			log.Fatalf("Failed to select containers: %v", err)
+		}
+		forEachContainer(ids, "unpause", func(id string) error {
+			_, err := client.Resume(ctx, &containerTask.ResumeRequest{ContainerId: id})
+			return err
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unpauseCmd)
+
+	unpauseCmd.Flags().StringArrayP("selector", "l", nil, "Select containers by label (key=value)")
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"kettle/pkg/oci"
+	"log"
+
+	"github.com/containerd/typeurl/v2"
+	"github.com/docker/go-units"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update [flags] <id>...",
+	Short: "Change the resource limits of containers",
+	Long: `Change the CPU, memory, pids and IO limits of containers. Running containers
+get the new limits right away, all of them keep them across restarts. Limits
+not given on the command line keep their values.
+
+	kctl update --memory 2g --cpus 1.5 web`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		selectors, _ := cmd.Flags().GetStringArray("selector")
+
+		opts, err := resourceOpts(cmd)
+		if err != nil {
+			log.Fatalf("Invalid resources: %v", err)
+		}
+		if len(opts) == 0 {
+			log.Fatalf("No resource limits given")
+		}
+		spec := &specs.Spec{}
+		for _, opt := range opts {
+			if err := opt(spec); err != nil {
+				log.Fatalf("Invalid resources: %v", err)
+			}
+		}
+		resources, err := typeurl.MarshalAnyToProto(spec.Linux.Resources)
+		if err != nil {
+			log.Fatalf("Failed to encode resources: %v", err)
+		}
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		ids, err := selectContainers(ctx, client, args, selectors, "")
+		if err != nil {
+			log.Fatalf("Failed to select containers: %v", err)
+		}
+		forEachContainer(ids, "update", func(id string) error {
+			_, err := client.Update(ctx, &containerTask.UpdateContainerRequest{
+				Container:  &containerTask.Container{ID: id},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"resources"}},
+				Resources:  resources,
+			})
+			return err
+		})
+	},
+}
+
+// addResourceFlags adds the flags resourceOpts reads
+func addResourceFlags(flags *pflag.FlagSet) {
+	flags.Float64("cpus", 0, "Number of CPUs the container may use")
+	flags.Uint64("cpu-shares", 0, "CPU shares relative to other containers (2-262144)")
+	flags.String("cpuset-cpus", "", "CPUs the container may run on (e.g. 0-2,4)")
+	flags.String("cpuset-mems", "", "Memory nodes the container may use (e.g. 0,1)")
+	flags.StringP("memory", "m", "", "Memory limit (e.g. 512m, 1g)")
+	flags.String("memory-swap", "", "Memory plus swap limit (e.g. 1g), -1 for unlimited swap")
+	flags.Int64("pids-limit", 0, "Maximum number of processes, -1 for unlimited")
+	flags.Uint16("io-weight", 0, "Block IO weight relative to other containers (10-1000)")
+}
+
+// resourceOpts turns the resource flags that were given into spec options
+func resourceOpts(cmd *cobra.Command) ([]oci.SpecOpts, error) {
+	flags := cmd.Flags()
+	var opts []oci.SpecOpts
+	if flags.Changed("cpus") {
+		cpus, _ := flags.GetFloat64("cpus")
+		opts = append(opts, oci.WithCPUs(cpus))
+	}
+	if flags.Changed("cpu-shares") {
+		shares, _ := flags.GetUint64("cpu-shares")
+		opts = append(opts, oci.WithCPUShares(shares))
+	}
+	cpusetCpus, _ := flags.GetString("cpuset-cpus")
+	cpusetMems, _ := flags.GetString("cpuset-mems")
+	if cpusetCpus != "" || cpusetMems != "" {
+		opts = append(opts, oci.WithCPUSet(cpusetCpus, cpusetMems))
+	}
+	if memory, _ := flags.GetString("memory"); memory != "" {
+		limit, err := units.RAMInBytes(memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory %q: %w", memory, err)
+		}
+		opts = append(opts, oci.WithMemoryLimit(limit))
+	}
+	if swap, _ := flags.GetString("memory-swap"); swap != "" {
+		limit := int64(-1)
+		if swap != "-1" {
+			var err error
+			if limit, err = units.RAMInBytes(swap); err != nil {
+				return nil, fmt.Errorf("invalid memory swap %q: %w", swap, err)
+			}
+		}
+		opts = append(opts, oci.WithMemorySwap(limit))
+	}
+	if flags.Changed("pids-limit") {
+		limit, _ := flags.GetInt64("pids-limit")
+		opts = append(opts, oci.WithPidsLimit(limit))
+	}
+	if flags.Changed("io-weight") {
+		weight, _ := flags.GetUint16("io-weight")
+		opts = append(opts, oci.WithIOWeight(weight))
+	}
+	return opts, nil
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	addResourceFlags(updateCmd.Flags())
+	updateCmd.Flags().StringArrayP("selector", "l", nil, "Select containers by label (key=value)")
+}
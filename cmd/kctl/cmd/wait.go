@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	containerTask "kettle/api/kettle"
+	client "kettle/client"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// waitCmd represents the wait command
+var waitCmd = &cobra.Command{
+	Use:   "wait <id>",
+	Short: "Wait for a container to exit",
+	Long: `Block until a container's process exits, print its exit status and exit
+with it. A container with a restart policy only counts as exited once the
+policy does not restart it anymore.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		execID, _ := cmd.Flags().GetString("exec-id")
+
+		client, err := client.GetGRPCTaskClient(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create task client: %v", err)
+		}
+		resp, err := client.Wait(ctx, &containerTask.WaitRequest{ContainerId: args[0], ExecId: execID})
+		if err != nil {
+			log.Fatalf("Failed to wait: %v", err)
+		}
+		fmt.Println(resp.ExitStatus)
+		os.Exit(int(resp.ExitStatus))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+
+	waitCmd.Flags().String("exec-id", "", "Wait for an exec process instead of the container's init")
+}
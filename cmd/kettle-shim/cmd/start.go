@@ -4,7 +4,6 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
 	server "kettle/server"
 	"log"
 
@@ -22,8 +21,6 @@ Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("start called")
-
 		id, err := cmd.Flags().GetString("id")
 		if err != nil {
 			log.Fatalf("Failed to get id flag: %v", err)
@@ -32,7 +29,10 @@ to quickly create a Cobra application.`,
 			log.Fatalf("Container ID is required")
 		}
 
-		server.StartShim(id)
+		// stdout is reserved for reporting the shim address to the daemon
+		if err := server.StartShim(id); err != nil {
+			log.Fatalf("Failed to start shim: %v", err)
+		}
 	},
 }
 
@@ -12,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	units "github.com/docker/go-units"
 	"github.com/spf13/cobra"
 )
 
@@ -62,7 +63,45 @@ to quickly create a Cobra application.`,
 	// has an action associated with it:
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("starting server")
-		server.CreateGRPCServer(context.TODO())
+		root, err := cmd.Flags().GetString("root")
+		if err != nil {
+			log.Fatalf("Failed to get root flag: %v", err)
+		}
+		logMaxSize, err := cmd.Flags().GetString("log-max-size")
+		if err != nil {
+			log.Fatalf("Failed to get log-max-size flag: %v", err)
+		}
+		maxSize, err := units.RAMInBytes(logMaxSize)
+		if err != nil {
+			log.Fatalf("Invalid log-max-size: %v", err)
+		}
+		logMaxFiles, err := cmd.Flags().GetInt("log-max-files")
+		if err != nil {
+			log.Fatalf("Failed to get log-max-files flag: %v", err)
+		}
+		snapshotter, err := cmd.Flags().GetString("snapshotter")
+		if err != nil {
+			log.Fatalf("Failed to get snapshotter flag: %v", err)
+		}
+		cniConfDir, err := cmd.Flags().GetString("cni-conf-dir")
+		if err != nil {
+			log.Fatalf("Failed to get cni-conf-dir flag: %v", err)
+		}
+		cniBinDir, err := cmd.Flags().GetString("cni-bin-dir")
+		if err != nil {
+			log.Fatalf("Failed to get cni-bin-dir flag: %v", err)
+		}
+		config := server.Config{
+			Root:        root,
+			LogMaxSize:  maxSize,
+			LogMaxFiles: logMaxFiles,
+			Snapshotter: snapshotter,
+			CNIConfDir:  cniConfDir,
+			CNIBinDir:   cniBinDir,
+		}
+		if err := server.CreateGRPCServer(context.TODO(), config); err != nil {
+			log.Fatalf("kettle stopped: %v", err)
+		}
 	},
 }
 
@@ -85,4 +124,10 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.Flags().String("root", "/var/lib/kettle", "directory for persistent daemon state")
+	rootCmd.Flags().String("log-max-size", "10m", "size at which container logs are rotated")
+	rootCmd.Flags().Int("log-max-files", 5, "number of rotated container logs to keep")
+	rootCmd.Flags().String("snapshotter", server.SnapshotterOverlay, "snapshotter for container root filesystems, overlayfs or native. overlayfs falls back to native where it is not supported")
+	rootCmd.Flags().String("cni-conf-dir", server.DefaultCNIConfDir, "directory of the CNI network configs, containers only get loopback without one")
+	rootCmd.Flags().String("cni-bin-dir", server.DefaultCNIBinDir, "directory of the CNI plugin binaries")
 }
@@ -0,0 +1,405 @@
+// Package archive moves files in and out of container filesystems as tar
+// streams, keeping ownership, permissions, timestamps and symlinks intact.
+//
+// The container may be running while its files are copied, so paths inside it
+// are never resolved once and used later: every file is reached through a
+// descriptor of its parent directory, opened with the kernel resolving the
+// path as if the container's root was the filesystem root. A path component
+// swapped for a symlink halfway through a copy cannot lead outside of it.
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// CopyOut writes a tar stream of path inside root to w. Entries are named
+// relative to the parent of path, so the stream's top level entry is the
+// base name of path. A symlink at path is archived as the link itself.
+func CopyOut(w io.Writer, root, path string) error {
+	rootFd, err := openRoot(root)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(rootFd)
+
+	path = filepath.Clean("/" + path)
+	dirFd, err := openInRoot(rootFd, filepath.Dir(path), unix.O_PATH|unix.O_DIRECTORY)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: filepath.Dir(path), Err: err}
+	}
+	defer unix.Close(dirFd)
+	leaf := filepath.Base(path)
+	if path == "/" {
+		leaf = "."
+	}
+
+	tw := tar.NewWriter(w)
+	if err := addEntry(tw, dirFd, leaf, leaf); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// addEntry archives leaf of the directory dirFd as name, along with everything
+// below it if it is a directory
+func addEntry(tw *tar.Writer, dirFd int, leaf, name string) error {
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirFd, leaf, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return &os.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	var f *os.File
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFREG, unix.S_IFDIR:
+		// nonblocking, a fifo swapped in for the file must not hang the copy
+		fd, err := unix.Openat(dirFd, leaf, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		f = os.NewFile(uintptr(fd), name)
+		defer f.Close()
+		// the header has to describe what is read, not what was there before
+		if err := unix.Fstat(fd, &st); err != nil {
+			return &os.PathError{Op: "stat", Path: name, Err: err}
+		}
+	case unix.S_IFSOCK:
+		// sockets cannot be archived, and are useless once copied anyway
+		return nil
+	}
+
+	hdr := &tar.Header{
+		Name:       filepath.ToSlash(name),
+		Mode:       int64(st.Mode & 07777),
+		Uid:        int(st.Uid),
+		Gid:        int(st.Gid),
+		ModTime:    time.Unix(st.Mtim.Unix()),
+		AccessTime: time.Unix(st.Atim.Unix()),
+	}
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFREG:
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = st.Size
+	case unix.S_IFDIR:
+		hdr.Typeflag = tar.TypeDir
+		hdr.Name += "/"
+	case unix.S_IFLNK:
+		hdr.Typeflag = tar.TypeSymlink
+		link, err := readlinkat(dirFd, leaf)
+		if err != nil {
+			return &os.PathError{Op: "readlink", Path: name, Err: err}
+		}
+		hdr.Linkname = link
+	case unix.S_IFIFO:
+		hdr.Typeflag = tar.TypeFifo
+	case unix.S_IFCHR, unix.S_IFBLK:
+		hdr.Typeflag = tar.TypeChar
+		if st.Mode&unix.S_IFMT == unix.S_IFBLK {
+			hdr.Typeflag = tar.TypeBlock
+		}
+		hdr.Devmajor = int64(unix.Major(st.Rdev))
+		hdr.Devminor = int64(unix.Minor(st.Rdev))
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeReg:
+		if _, err := io.CopyN(tw, f, hdr.Size); err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+	case tar.TypeDir:
+		names, err := f.Readdirnames(-1)
+		if err != nil {
+			return &os.PathError{Op: "readdir", Path: name, Err: err}
+		}
+		sort.Strings(names)
+		for _, child := range names {
+			if err := addEntry(tw, int(f.Fd()), child, filepath.Join(name, child)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CopyIn extracts the tar stream r to dest inside root, following the rules of
+// cp: if dest is an existing directory the stream's top level entry is placed
+// inside it, otherwise the top level entry is created as dest itself.
+func CopyIn(r io.Reader, root, dest string) error {
+	rootFd, err := openRoot(root)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(rootFd)
+
+	dest = filepath.Clean("/" + dest)
+	x := &extractor{rootFd: rootFd, dir: dest}
+	if !isDirInRoot(rootFd, dest) {
+		x.dir, x.rename = filepath.Dir(dest), filepath.Base(dest)
+	}
+
+	type dirTimes struct {
+		path string
+		hdr  *tar.Header
+	}
+	// directories get their final mode and times once their contents are in
+	var dirs []dirTimes
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name, err := entryName(hdr.Name, x.rename)
+		if err != nil {
+			return err
+		}
+		if err := x.extract(tr, hdr, name); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			dirs = append(dirs, dirTimes{filepath.Join(x.dir, name), hdr})
+		}
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		fd, err := openInRoot(rootFd, dirs[i].path, unix.O_RDONLY|unix.O_DIRECTORY)
+		if err != nil {
+			return &os.PathError{Op: "open", Path: dirs[i].path, Err: err}
+		}
+		err = unix.Fchmod(fd, uint32(dirs[i].hdr.Mode&07777))
+		if err == nil {
+			setTimes(fd, ".", dirs[i].hdr)
+		}
+		unix.Close(fd)
+		if err != nil {
+			return &os.PathError{Op: "chmod", Path: dirs[i].path, Err: err}
+		}
+	}
+	return nil
+}
+
+// entryName cleans an entry name, refusing names that climb out of the
+// extraction directory, and swaps its first component for rename if set
+func entryName(name, rename string) (string, error) {
+	name = filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("refusing to extract %q outside of the destination", name)
+	}
+	if rename == "" {
+		return name, nil
+	}
+	if i := strings.IndexRune(name, filepath.Separator); i >= 0 {
+		return filepath.Join(rename, name[i+1:]), nil
+	}
+	return rename, nil
+}
+
+// extractor creates the entries of a stream in dir, a path inside the root
+// rootFd refers to
+type extractor struct {
+	rootFd int
+	dir    string
+	rename string
+}
+
+func (x *extractor) extract(tr *tar.Reader, hdr *tar.Header, name string) error {
+	parentFd, err := openInRoot(x.rootFd, filepath.Join(x.dir, filepath.Dir(name)), unix.O_PATH|unix.O_DIRECTORY)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(parentFd)
+	leaf := filepath.Base(name)
+
+	mode := uint32(hdr.Mode & 07777)
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		var st unix.Stat_t
+		if err := unix.Fstatat(parentFd, leaf, &st, unix.AT_SYMLINK_NOFOLLOW); err == nil && st.Mode&unix.S_IFMT == unix.S_IFDIR {
+			break
+		}
+		if err := unix.Mkdirat(parentFd, leaf, 0700); err != nil {
+			return err
+		}
+	case tar.TypeReg:
+		if err := removeNonDir(parentFd, leaf); err != nil {
+			return err
+		}
+		fd, err := unix.Openat(parentFd, leaf, unix.O_CREAT|unix.O_EXCL|unix.O_WRONLY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0600)
+		if err != nil {
+			return err
+		}
+		f := os.NewFile(uintptr(fd), name)
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := removeNonDir(parentFd, leaf); err != nil {
+			return err
+		}
+		if err := unix.Symlinkat(hdr.Linkname, parentFd, leaf); err != nil {
+			return err
+		}
+	case tar.TypeLink:
+		if err := removeNonDir(parentFd, leaf); err != nil {
+			return err
+		}
+		// link targets are entries of the same stream, renamed along with it
+		target, err := entryName(hdr.Linkname, x.rename)
+		if err != nil {
+			return err
+		}
+		targetFd, err := openInRoot(x.rootFd, filepath.Join(x.dir, filepath.Dir(target)), unix.O_PATH|unix.O_DIRECTORY)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(targetFd)
+		return unix.Linkat(targetFd, filepath.Base(target), parentFd, leaf, 0)
+	case tar.TypeFifo:
+		if err := removeNonDir(parentFd, leaf); err != nil {
+			return err
+		}
+		if err := unix.Mknodat(parentFd, leaf, unix.S_IFIFO|mode&0777, 0); err != nil {
+			return err
+		}
+	default:
+		// device nodes and friends have no business being copied around
+		return nil
+	}
+
+	if err := unix.Fchownat(parentFd, leaf, hdr.Uid, hdr.Gid, unix.AT_SYMLINK_NOFOLLOW); err != nil && !errors.Is(err, unix.EPERM) {
+		return err
+	}
+	if hdr.Typeflag == tar.TypeSymlink {
+		setTimes(parentFd, leaf, hdr)
+		return nil
+	}
+	if hdr.Typeflag != tar.TypeDir {
+		if err := chmodAt(parentFd, leaf, mode); err != nil {
+			return err
+		}
+		setTimes(parentFd, leaf, hdr)
+	}
+	return nil
+}
+
+// OpenInRoot opens path for reading with symlinks, ".." included, resolved as
+// if root was the filesystem root, so that a file of an untrusted tree can be
+// read without following a link out of it
+func OpenInRoot(root, path string) (*os.File, error) {
+	rootFd, err := openRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(rootFd)
+	fd, err := openInRoot(rootFd, path, unix.O_RDONLY)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: filepath.Join(root, path), Err: err}
+	}
+	return os.NewFile(uintptr(fd), filepath.Join(root, path)), nil
+}
+
+// openRoot opens the directory paths are resolved in
+func openRoot(root string) (int, error) {
+	fd, err := unix.Open(root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	return fd, nil
+}
+
+// openInRoot opens path with the kernel resolving it, symlinks included, as
+// if the directory rootFd refers to was the filesystem root
+func openInRoot(rootFd int, path string, flags uint64) (int, error) {
+	path = strings.TrimPrefix(filepath.Clean("/"+path), "/")
+	if path == "" {
+		path = "."
+	}
+	return unix.Openat2(rootFd, path, &unix.OpenHow{
+		Flags:   flags | unix.O_CLOEXEC,
+		Resolve: unix.RESOLVE_IN_ROOT | unix.RESOLVE_NO_MAGICLINKS,
+	})
+}
+
+// isDirInRoot tells whether path inside rootFd is a directory, following a
+// symlink at path
+func isDirInRoot(rootFd int, path string) bool {
+	fd, err := openInRoot(rootFd, path, unix.O_PATH)
+	if err != nil {
+		return false
+	}
+	defer unix.Close(fd)
+	var st unix.Stat_t
+	return unix.Fstat(fd, &st) == nil && st.Mode&unix.S_IFMT == unix.S_IFDIR
+}
+
+func removeNonDir(parentFd int, leaf string) error {
+	var st unix.Stat_t
+	err := unix.Fstatat(parentFd, leaf, &st, unix.AT_SYMLINK_NOFOLLOW)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if st.Mode&unix.S_IFMT == unix.S_IFDIR {
+		return fmt.Errorf("cannot overwrite directory %s", leaf)
+	}
+	return unix.Unlinkat(parentFd, leaf, 0)
+}
+
+// chmodAt changes the mode of leaf through a descriptor of its own, as
+// fchmodat cannot be told to leave symlinks alone
+func chmodAt(parentFd int, leaf string, mode uint32) error {
+	fd, err := unix.Openat(parentFd, leaf, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	return unix.Fchmod(fd, mode)
+}
+
+func readlinkat(dirFd int, leaf string) (string, error) {
+	for size := 256; ; size *= 2 {
+		buf := make([]byte, size)
+		n, err := unix.Readlinkat(dirFd, leaf, buf)
+		if err != nil {
+			return "", err
+		}
+		if n < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
+
+func setTimes(dirFd int, leaf string, hdr *tar.Header) {
+	atime := hdr.AccessTime
+	if atime.IsZero() {
+		atime = hdr.ModTime
+	}
+	ts := []unix.Timespec{toTimespec(atime), toTimespec(hdr.ModTime)}
+	unix.UtimesNanoAt(dirFd, leaf, ts, unix.AT_SYMLINK_NOFOLLOW)
+}
+
+func toTimespec(t time.Time) unix.Timespec {
+	if t.IsZero() {
+		return unix.Timespec{Sec: 0, Nsec: unix.UTIME_OMIT}
+	}
+	return unix.NsecToTimespec(t.UnixNano())
+}
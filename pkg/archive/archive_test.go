@@ -0,0 +1,282 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTar builds a tar stream of the given headers, regular files get their
+// name as content
+func writeTar(t *testing.T, hdrs ...*tar.Header) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range hdrs {
+		var data []byte
+		if hdr.Typeflag == tar.TypeReg {
+			data = []byte(hdr.Name)
+			hdr.Size = int64(len(data))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestCopyRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "app", "conf"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "app", "conf", "app.conf"), []byte("listen 80\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("conf/app.conf", filepath.Join(src, "app", "current")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := CopyOut(&buf, src, "/app"); err != nil {
+		t.Fatalf("CopyOut: %v", err)
+	}
+	dst := t.TempDir()
+	// dest does not exist, the top level entry is renamed to it
+	if err := CopyIn(&buf, dst, "/srv"); err != nil {
+		t.Fatalf("CopyIn: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, "srv", "conf", "app.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "listen 80\n" {
+		t.Errorf("app.conf = %q", data)
+	}
+	fi, err := os.Stat(filepath.Join(dst, "srv", "conf", "app.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0640 {
+		t.Errorf("app.conf mode = %v, want 0640", fi.Mode().Perm())
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "srv", "conf")); err != nil || fi.Mode().Perm() != 0750 {
+		t.Errorf("conf = %v, %v, want a 0750 directory", fi, err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "srv", "current")); err != nil || target != "conf/app.conf" {
+		t.Errorf("current links to %q, %v", target, err)
+	}
+}
+
+func TestCopyInExistingDir(t *testing.T) {
+	dst := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dst, "tmp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	buf := writeTar(t, &tar.Header{Typeflag: tar.TypeReg, Name: "file", Mode: 0644})
+	if err := CopyIn(buf, dst, "/tmp"); err != nil {
+		t.Fatalf("CopyIn: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "tmp", "file")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCopyInHardlinkRenamed(t *testing.T) {
+	dst := t.TempDir()
+	buf := writeTar(t,
+		&tar.Header{Typeflag: tar.TypeDir, Name: "data/", Mode: 0755},
+		&tar.Header{Typeflag: tar.TypeReg, Name: "data/a", Mode: 0644},
+		&tar.Header{Typeflag: tar.TypeLink, Name: "data/b", Linkname: "data/a"},
+	)
+	if err := CopyIn(buf, dst, "/copy"); err != nil {
+		t.Fatalf("CopyIn: %v", err)
+	}
+	a, err := os.Stat(filepath.Join(dst, "copy", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.Stat(filepath.Join(dst, "copy", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(a, b) {
+		t.Error("b is not a hard link to a")
+	}
+}
+
+func TestCopyInStaysInRoot(t *testing.T) {
+	outside := t.TempDir()
+	up := "../../../../../../../.."
+	tests := []struct {
+		name string
+		// links are symlinks in the root before the copy
+		links map[string]string
+		dest  string
+		hdrs  []*tar.Header
+	}{
+		{"dot dot", nil, "/", []*tar.Header{
+			{Typeflag: tar.TypeReg, Name: up + outside + "/pwned", Mode: 0644},
+		}},
+		{"dot dot dest", nil, up + outside, []*tar.Header{
+			{Typeflag: tar.TypeReg, Name: "pwned", Mode: 0644},
+		}},
+		{"absolute symlink", nil, "/", []*tar.Header{
+			{Typeflag: tar.TypeSymlink, Name: "escape", Linkname: outside},
+			{Typeflag: tar.TypeReg, Name: "escape/pwned", Mode: 0644},
+		}},
+		{"relative symlink", nil, "/", []*tar.Header{
+			{Typeflag: tar.TypeSymlink, Name: "escape", Linkname: up + outside},
+			{Typeflag: tar.TypeReg, Name: "escape/pwned", Mode: 0644},
+		}},
+		{"symlink loop", nil, "/", []*tar.Header{
+			{Typeflag: tar.TypeSymlink, Name: "loop", Linkname: "loop"},
+			{Typeflag: tar.TypeReg, Name: "loop/pwned", Mode: 0644},
+		}},
+		{"hard link", nil, "/", []*tar.Header{
+			{Typeflag: tar.TypeLink, Name: "pwned", Linkname: up + outside + "/target"},
+		}},
+		{"absolute symlink dest", map[string]string{"escape": outside}, "/escape", []*tar.Header{
+			{Typeflag: tar.TypeReg, Name: "pwned", Mode: 0644},
+		}},
+		{"relative symlink dest", map[string]string{"escape": up + outside}, "/escape", []*tar.Header{
+			{Typeflag: tar.TypeReg, Name: "pwned", Mode: 0644},
+		}},
+		{"symlink loop dest", map[string]string{"loop": "loop"}, "/loop/dir", []*tar.Header{
+			{Typeflag: tar.TypeReg, Name: "pwned", Mode: 0644},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, target := range tt.links {
+				if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// errors are fine, writing outside of root is not
+			CopyIn(writeTar(t, tt.hdrs...), root, tt.dest)
+			if _, err := os.Lstat(filepath.Join(outside, "pwned")); !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("extraction escaped the root: %v", err)
+			}
+		})
+	}
+}
+
+func TestCopyOutStaysInRoot(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("outside"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "secret"), []byte("inside"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	up := "../../../../../../../.."
+	links := map[string]string{
+		"escape": outside,
+		"rel":    up + outside,
+		"loop":   "loop",
+		"top":    "/",
+		"parent": up,
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		// want is the content copied out, empty when the copy fails
+		want string
+	}{
+		{"/escape/secret", ""},
+		{"/rel/secret", ""},
+		{"/" + up + outside + "/secret", ""},
+		{"/loop/secret", ""},
+		// links are followed as if root was /
+		{"/top/secret", "inside"},
+		{"/parent/secret", "inside"},
+		{"/" + up + "/secret", "inside"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		err := CopyOut(&buf, root, tt.path)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("CopyOut(%q) copied from outside of root", tt.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CopyOut(%q): %v", tt.path, err)
+			continue
+		}
+		tr := tar.NewReader(&buf)
+		if _, err := tr.Next(); err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := io.ReadAll(tr); string(data) != tt.want {
+			t.Errorf("CopyOut(%q) copied %q, want %q", tt.path, data, tt.want)
+		}
+	}
+
+	// the symlink itself is archived as a link
+	var buf bytes.Buffer
+	if err := CopyOut(&buf, root, "/escape"); err != nil {
+		t.Fatalf("CopyOut: %v", err)
+	}
+	hdr, err := tar.NewReader(&buf).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hdr.Typeflag != tar.TypeSymlink || hdr.Linkname != outside {
+		t.Errorf("escape archived as type %c to %q", hdr.Typeflag, hdr.Linkname)
+	}
+}
+
+func TestOpenInRoot(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "passwd"), []byte("outside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "passwd"), []byte("inside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../../../../../.."+outside+"/passwd", filepath.Join(root, "etc", "passwd")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/passwd", filepath.Join(root, "etc", "group")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenInRoot(root, "/etc/passwd"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenInRoot through a symlink out of root = %v, want not exist", err)
+	}
+	f, err := OpenInRoot(root, "/etc/group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data := make([]byte, 16)
+	n, _ := f.Read(data)
+	if string(data[:n]) != "inside" {
+		t.Errorf("/etc/group read %q, want the root's /passwd", data[:n])
+	}
+}
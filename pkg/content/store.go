@@ -0,0 +1,459 @@
+// Package content keeps the blobs of images by digest.
+//
+// Committed blobs live under <root>/blobs/<algorithm>/<encoded digest> and
+// never change. A write goes to an ingest under <root>/ingest first, holding
+// the data written so far along with the ref and expected size of the write,
+// and the data is only moved into blobs/ once its digest checks out. An ingest
+// left behind by an interrupted write is picked up by the next writer of the
+// same ref, which carries on from where the first one stopped.
+//
+// Store implements containerd's content.Store, so the image walks and fetch
+// handlers of containerd work on it. Labels are not kept.
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Store is a content store under a root directory
+type Store struct {
+	root string
+	// mu guards writing, the refs of the ingests a writer is open for
+	mu      sync.Mutex
+	writing map[string]struct{}
+}
+
+var _ content.Store = (*Store)(nil)
+
+// ingest is the record kept next to the data of an ingest
+type ingest struct {
+	Ref       string        `json:"ref"`
+	Total     int64         `json:"total,omitempty"`
+	Expected  digest.Digest `json:"expected,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+}
+
+// NewStore opens the content store under root, creating it if needed
+func NewStore(root string) (*Store, error) {
+	for _, dir := range []string{"blobs", "ingest"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create content store: %w", err)
+		}
+	}
+	return &Store{root: root, writing: map[string]struct{}{}}, nil
+}
+
+func (s *Store) blobPath(dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", fmt.Errorf("invalid digest %q: %v: %w", dgst, err, errdefs.ErrInvalidArgument)
+	}
+	return filepath.Join(s.root, "blobs", dgst.Algorithm().String(), dgst.Encoded()), nil
+}
+
+// ingestDir is where the ingest of ref is kept, named by the digest of the ref
+// as refs are free-form
+func (s *Store) ingestDir(ref string) string {
+	return filepath.Join(s.root, "ingest", digest.FromString(ref).Encoded())
+}
+
+func (s *Store) Info(ctx context.Context, dgst digest.Digest) (content.Info, error) {
+	path, err := s.blobPath(dgst)
+	if err != nil {
+		return content.Info{}, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return content.Info{}, fmt.Errorf("content %s: %w", dgst, errdefs.ErrNotFound)
+		}
+		return content.Info{}, err
+	}
+	return blobInfo(dgst, fi), nil
+}
+
+func blobInfo(dgst digest.Digest, fi os.FileInfo) content.Info {
+	// blobs never change once committed
+	return content.Info{
+		Digest:    dgst,
+		Size:      fi.Size(),
+		CreatedAt: fi.ModTime(),
+		UpdatedAt: fi.ModTime(),
+	}
+}
+
+// Update has nothing to update, labels are not kept
+func (s *Store) Update(ctx context.Context, info content.Info, fieldpaths ...string) (content.Info, error) {
+	return content.Info{}, fmt.Errorf("content labels: %w", errdefs.ErrNotImplemented)
+}
+
+// Walk calls fn for every committed blob. Filters are not supported.
+func (s *Store) Walk(ctx context.Context, fn content.WalkFunc, filters ...string) error {
+	if len(filters) > 0 {
+		return fmt.Errorf("content filters: %w", errdefs.ErrNotImplemented)
+	}
+	algorithms, err := os.ReadDir(filepath.Join(s.root, "blobs"))
+	if err != nil {
+		return err
+	}
+	for _, alg := range algorithms {
+		blobs, err := os.ReadDir(filepath.Join(s.root, "blobs", alg.Name()))
+		if err != nil {
+			return err
+		}
+		for _, blob := range blobs {
+			dgst := digest.NewDigestFromEncoded(digest.Algorithm(alg.Name()), blob.Name())
+			if dgst.Validate() != nil {
+				continue
+			}
+			fi, err := blob.Info()
+			if os.IsNotExist(err) {
+				// deleted while we walked
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := fn(blobInfo(dgst, fi)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, dgst digest.Digest) error {
+	path, err := s.blobPath(dgst)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("content %s: %w", dgst, errdefs.ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Store) ReaderAt(ctx context.Context, desc ocispec.Descriptor) (content.ReaderAt, error) {
+	path, err := s.blobPath(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("content %s: %w", desc.Digest, errdefs.ErrNotFound)
+		}
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &readerAt{File: f, size: fi.Size()}, nil
+}
+
+type readerAt struct {
+	*os.File
+	size int64
+}
+
+func (r *readerAt) Size() int64 {
+	return r.size
+}
+
+func (s *Store) Status(ctx context.Context, ref string) (content.Status, error) {
+	return s.status(s.ingestDir(ref))
+}
+
+// status reads the status of the ingest kept in dir
+func (s *Store) status(dir string) (content.Status, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "ingest.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return content.Status{}, fmt.Errorf("ingest %s: %w", filepath.Base(dir), errdefs.ErrNotFound)
+		}
+		return content.Status{}, err
+	}
+	var in ingest
+	if err := json.Unmarshal(data, &in); err != nil {
+		return content.Status{}, fmt.Errorf("invalid ingest %s: %w", filepath.Base(dir), err)
+	}
+	st := content.Status{
+		Ref:       in.Ref,
+		Total:     in.Total,
+		Expected:  in.Expected,
+		StartedAt: in.StartedAt,
+		UpdatedAt: in.StartedAt,
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "data")); err == nil {
+		st.Offset = fi.Size()
+		st.UpdatedAt = fi.ModTime()
+	}
+	return st, nil
+}
+
+// ListStatuses returns the status of the ingests whose ref matches any of the
+// filters, regular expressions, or of all of them without filters
+func (s *Store) ListStatuses(ctx context.Context, filters ...string) ([]content.Status, error) {
+	var res []*regexp.Regexp
+	for _, f := range filters {
+		re, err := regexp.Compile(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %v: %w", f, err, errdefs.ErrInvalidArgument)
+		}
+		res = append(res, re)
+	}
+	entries, err := os.ReadDir(filepath.Join(s.root, "ingest"))
+	if err != nil {
+		return nil, err
+	}
+	var statuses []content.Status
+	for _, entry := range entries {
+		st, err := s.status(filepath.Join(s.root, "ingest", entry.Name()))
+		if errdefs.IsNotFound(err) {
+			// committed or aborted while we listed, or not set up yet
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if matchRef(st.Ref, res) {
+			statuses = append(statuses, st)
+		}
+	}
+	return statuses, nil
+}
+
+func matchRef(ref string, res []*regexp.Regexp) bool {
+	if len(res) == 0 {
+		return true
+	}
+	for _, re := range res {
+		if re.MatchString(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// Abort removes the ingest of ref along with the data written to it. An
+// ingest with a writer open gets ErrUnavailable.
+func (s *Store) Abort(ctx context.Context, ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.writing[ref]; ok {
+		return fmt.Errorf("ingest %s is being written: %w", ref, errdefs.ErrUnavailable)
+	}
+	dir := s.ingestDir(ref)
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("ingest %s: %w", ref, errdefs.ErrNotFound)
+		}
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// Writer opens the ingest of the ref given with content.WithRef, resuming it
+// if it exists. A ref has one writer at a time, others get ErrUnavailable
+// until it is closed. A blob of the expected descriptor that is there already
+// gets ErrAlreadyExists.
+func (s *Store) Writer(ctx context.Context, opts ...content.WriterOpt) (content.Writer, error) {
+	var wOpts content.WriterOpts
+	for _, opt := range opts {
+		if err := opt(&wOpts); err != nil {
+			return nil, err
+		}
+	}
+	if wOpts.Ref == "" {
+		return nil, fmt.Errorf("ingest ref is required: %w", errdefs.ErrInvalidArgument)
+	}
+	if wOpts.Desc.Digest != "" {
+		if _, err := s.Info(ctx, wOpts.Desc.Digest); err == nil {
+			return nil, fmt.Errorf("content %s: %w", wOpts.Desc.Digest, errdefs.ErrAlreadyExists)
+		} else if !errdefs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	if _, ok := s.writing[wOpts.Ref]; ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("ingest %s is being written: %w", wOpts.Ref, errdefs.ErrUnavailable)
+	}
+	s.writing[wOpts.Ref] = struct{}{}
+	s.mu.Unlock()
+
+	w, err := s.openIngest(wOpts.Ref, wOpts.Desc.Size, wOpts.Desc.Digest)
+	if err != nil {
+		s.unlock(wOpts.Ref)
+		return nil, err
+	}
+	return w, nil
+}
+
+// openIngest opens the ingest of ref, creating it if needed. The data of an
+// existing ingest is read back to pick up its digest where it left off.
+func (s *Store) openIngest(ref string, total int64, expected digest.Digest) (*writer, error) {
+	dir := s.ingestDir(ref)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	st, err := s.status(dir)
+	if err != nil || !resumable(st, ref, total) {
+		in := ingest{Ref: ref, Total: total, Expected: expected, StartedAt: time.Now()}
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Remove(filepath.Join(dir, "data")); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "ingest.json"), data, 0600); err != nil {
+			return nil, err
+		}
+		st = content.Status{Ref: ref, Total: total, Expected: expected, StartedAt: in.StartedAt}
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "data"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	digester := digest.Canonical.Digester()
+	offset, err := io.Copy(digester.Hash(), f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to resume ingest %s: %w", ref, err)
+	}
+	return &writer{
+		s:        s,
+		dir:      dir,
+		file:     f,
+		digester: digester,
+		offset:   offset,
+		status:   st,
+	}, nil
+}
+
+// resumable tells whether the ingest st can be carried on with by a write of
+// total bytes to ref, total being 0 when unknown
+func resumable(st content.Status, ref string, total int64) bool {
+	if st.Ref != ref {
+		return false
+	}
+	return total == 0 || st.Total == total && st.Offset <= total
+}
+
+func (s *Store) unlock(ref string) {
+	s.mu.Lock()
+	delete(s.writing, ref)
+	s.mu.Unlock()
+}
+
+// writer writes to an ingest, its file is positioned at the end of the data
+type writer struct {
+	s        *Store
+	dir      string
+	file     *os.File
+	digester digest.Digester
+	offset   int64
+	status   content.Status
+	closed   bool
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.digester.Hash().Write(p[:n])
+	w.offset += int64(n)
+	return n, err
+}
+
+func (w *writer) Digest() digest.Digest {
+	return w.digester.Digest()
+}
+
+func (w *writer) Status() (content.Status, error) {
+	st := w.status
+	st.Offset = w.offset
+	st.UpdatedAt = time.Now()
+	return st, nil
+}
+
+// Truncate starts the write over, only truncating to 0 is supported
+func (w *writer) Truncate(size int64) error {
+	if size != 0 {
+		return fmt.Errorf("truncating an ingest to %d: %w", size, errdefs.ErrNotImplemented)
+	}
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.digester.Hash().Reset()
+	w.offset = 0
+	return nil
+}
+
+// Commit moves the data into the store once its size and digest check out.
+// The ingest is closed either way, one that failed to check out is kept for
+// another writer to truncate or abort.
+func (w *writer) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	defer w.Close()
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	if size > 0 && size != w.offset {
+		return fmt.Errorf("ingest %s has %d bytes, not %d: %w", w.status.Ref, w.offset, size, errdefs.ErrFailedPrecondition)
+	}
+	dgst := w.digester.Digest()
+	if expected != "" && expected != dgst {
+		return fmt.Errorf("ingest %s has digest %s, not %s: %w", w.status.Ref, dgst, expected, errdefs.ErrFailedPrecondition)
+	}
+
+	path, err := w.s.blobPath(dgst)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		os.RemoveAll(w.dir)
+		return fmt.Errorf("content %s: %w", dgst, errdefs.ErrAlreadyExists)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := w.file.Chmod(0400); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(w.dir, "data"), path); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", dgst, err)
+	}
+	return os.RemoveAll(w.dir)
+}
+
+// Close closes the writer, leaving the ingest for a later writer to resume
+func (w *writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	w.s.unlock(w.status.Ref)
+	return err
+}
@@ -0,0 +1,292 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func descriptor(data string) ocispec.Descriptor {
+	return ocispec.Descriptor{MediaType: "application/octet-stream", Digest: digest.FromString(data), Size: int64(len(data))}
+}
+
+// readBlob returns the content of the committed blob dgst
+func readBlob(t *testing.T, s *Store, dgst digest.Digest) string {
+	t.Helper()
+	data, err := content.ReadBlob(context.Background(), s, ocispec.Descriptor{Digest: dgst})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteBlob(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	desc := descriptor("hello world")
+	if err := content.WriteBlob(ctx, s, "ref", strings.NewReader("hello world"), desc); err != nil {
+		t.Fatal(err)
+	}
+	if got := readBlob(t, s, desc.Digest); got != "hello world" {
+		t.Errorf("blob = %q", got)
+	}
+	info, err := s.Info(ctx, desc.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Digest != desc.Digest || info.Size != desc.Size {
+		t.Errorf("info = %+v, want %s of %d bytes", info, desc.Digest, desc.Size)
+	}
+	// the ingest is gone once committed
+	if _, err := s.Status(ctx, "ref"); !errdefs.IsNotFound(err) {
+		t.Errorf("Status of a committed write = %v, want not found", err)
+	}
+	// writing a blob that is there already is a no-op
+	if _, err := s.Writer(ctx, content.WithRef("again"), content.WithDescriptor(desc)); !errdefs.IsAlreadyExists(err) {
+		t.Errorf("Writer of a stored blob = %v, want already exists", err)
+	}
+	if err := content.WriteBlob(ctx, s, "again", strings.NewReader("hello world"), desc); err != nil {
+		t.Errorf("WriteBlob of a stored blob: %v", err)
+	}
+}
+
+func TestCommitChecks(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		size     int64
+		expected digest.Digest
+	}{
+		{"wrong size", 3, ""},
+		{"wrong digest", 0, digest.FromString("something else")},
+	}
+	for _, tt := range tests {
+		s := newTestStore(t)
+		w, err := s.Writer(ctx, content.WithRef("ref"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, "data"); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Commit(ctx, tt.size, tt.expected); !errdefs.IsFailedPrecondition(err) {
+			t.Errorf("%s: Commit = %v, want a failed precondition", tt.name, err)
+		}
+		if _, err := s.Info(ctx, digest.FromString("data")); !errdefs.IsNotFound(err) {
+			t.Errorf("%s: blob committed: %v", tt.name, err)
+		}
+		// a failed commit leaves the ingest to be aborted
+		if err := s.Abort(ctx, "ref"); err != nil {
+			t.Errorf("%s: Abort: %v", tt.name, err)
+		}
+	}
+}
+
+func TestResumeWrite(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	data := "0123456789abcdefghij"
+	desc := descriptor(data)
+
+	w, err := s.Writer(ctx, content.WithRef("ref"), content.WithDescriptor(desc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, data[:8]); err != nil {
+		t.Fatal(err)
+	}
+	// one writer per ref
+	if _, err := s.Writer(ctx, content.WithRef("ref"), content.WithDescriptor(desc)); !errdefs.IsUnavailable(err) {
+		t.Errorf("second Writer = %v, want unavailable", err)
+	}
+	if err := s.Abort(ctx, "ref"); !errdefs.IsUnavailable(err) {
+		t.Errorf("Abort while written = %v, want unavailable", err)
+	}
+	// the write is interrupted
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := s.Status(ctx, "ref")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Ref != "ref" || st.Offset != 8 || st.Total != desc.Size || st.Expected != desc.Digest {
+		t.Errorf("status = %+v", st)
+	}
+	// and carried on by the next writer of the ref
+	w, err = s.Writer(ctx, content.WithRef("ref"), content.WithDescriptor(desc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := content.Copy(ctx, w, strings.NewReader(data), desc.Size, desc.Digest); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if got := readBlob(t, s, desc.Digest); got != data {
+		t.Errorf("blob = %q, want %q", got, data)
+	}
+}
+
+func TestResumeDifferentBlob(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	w, err := s.Writer(ctx, content.WithRef("ref"), content.WithDescriptor(descriptor("first blob")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "first")
+	w.Close()
+
+	// the ref now names a blob of another size, the ingest starts over
+	desc := descriptor("second")
+	w, err = s.Writer(ctx, content.WithRef("ref"), content.WithDescriptor(desc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := w.Status(); st.Offset != 0 || st.Total != desc.Size {
+		t.Errorf("status = %+v, want a new ingest", st)
+	}
+	if err := content.Copy(ctx, w, strings.NewReader("second"), desc.Size, desc.Digest); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	w, err := s.Writer(ctx, content.WithRef("ref"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "garbage")
+	if err := w.Truncate(3); !errdefs.IsNotImplemented(err) {
+		t.Errorf("Truncate(3) = %v, want not implemented", err)
+	}
+	if err := w.Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "data")
+	if err := w.Commit(ctx, 4, digest.FromString("data")); err != nil {
+		t.Fatalf("Commit after Truncate: %v", err)
+	}
+}
+
+func TestWalkDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	want := map[digest.Digest]bool{}
+	for _, data := range []string{"a", "b", "c"} {
+		desc := descriptor(data)
+		if err := content.WriteBlob(ctx, s, data, strings.NewReader(data), desc); err != nil {
+			t.Fatal(err)
+		}
+		want[desc.Digest] = true
+	}
+	// neither unfinished writes nor stray files are blobs
+	w, err := s.Writer(ctx, content.WithRef("unfinished"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "d")
+	w.Close()
+	if err := os.WriteFile(filepath.Join(s.root, "blobs", "sha256", "stray"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	walked := map[digest.Digest]bool{}
+	if err := s.Walk(ctx, func(info content.Info) error {
+		walked[info.Digest] = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(walked) != len(want) {
+		t.Errorf("walked %v, want %v", walked, want)
+	}
+	for dgst := range want {
+		if !walked[dgst] {
+			t.Errorf("%s not walked", dgst)
+		}
+	}
+
+	b := digest.FromString("b")
+	if err := s.Delete(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, b); !errdefs.IsNotFound(err) {
+		t.Errorf("second Delete = %v, want not found", err)
+	}
+	if _, err := s.ReaderAt(ctx, ocispec.Descriptor{Digest: b}); !errdefs.IsNotFound(err) {
+		t.Errorf("ReaderAt of a deleted blob = %v, want not found", err)
+	}
+	if _, err := s.Info(ctx, "sha256:../../etc"); !errdefs.IsInvalidArgument(err) {
+		t.Errorf("Info of an invalid digest = %v, want invalid argument", err)
+	}
+}
+
+func TestListStatuses(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	for _, ref := range []string{"layer-sha256:1", "layer-sha256:2", "config-sha256:3"} {
+		w, err := s.Writer(ctx, content.WithRef(ref))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(bytes.Repeat([]byte{'x'}, 10))
+		w.Close()
+	}
+	tests := []struct {
+		filters []string
+		want    int
+	}{
+		{nil, 3},
+		{[]string{"^layer-"}, 2},
+		{[]string{"^layer-", "^config-"}, 3},
+		{[]string{"^manifest-"}, 0},
+	}
+	for _, tt := range tests {
+		statuses, err := s.ListStatuses(ctx, tt.filters...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(statuses) != tt.want {
+			t.Errorf("ListStatuses(%v) = %d statuses, want %d", tt.filters, len(statuses), tt.want)
+		}
+		for _, st := range statuses {
+			if st.Offset != 10 {
+				t.Errorf("status of %s has offset %d, want 10", st.Ref, st.Offset)
+			}
+		}
+	}
+	if _, err := s.ListStatuses(ctx, "("); !errdefs.IsInvalidArgument(err) {
+		t.Errorf("ListStatuses with an invalid filter = %v", err)
+	}
+
+	if err := s.Abort(ctx, "layer-sha256:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Abort(ctx, "layer-sha256:1"); !errdefs.IsNotFound(err) {
+		t.Errorf("second Abort = %v, want not found", err)
+	}
+	if statuses, _ := s.ListStatuses(ctx); len(statuses) != 2 {
+		t.Errorf("%d statuses after Abort, want 2", len(statuses))
+	}
+}
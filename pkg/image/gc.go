@@ -0,0 +1,128 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// staleIngest is how long an unfinished write is kept around to be resumed
+const staleIngest = 24 * time.Hour
+
+// GCStats reports what a garbage collection removed
+type GCStats struct {
+	Removed []digest.Digest
+	// Freed is the size in bytes of the removed blobs
+	Freed int64
+	// Ingests is the number of abandoned writes that were aborted
+	Ingests  int
+	Duration time.Duration
+}
+
+// GC removes every blob that is not reachable from an image, from one of
+// roots or from a lease, along with writes abandoned for longer than a day.
+// Pulls wait while it runs.
+func (s *Store) GC(ctx context.Context, roots ...ocispec.Descriptor) (GCStats, error) {
+	start := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	marked, err := s.mark(ctx, roots)
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	var stats GCStats
+	var unreferenced []content.Info
+	if err := s.content.Walk(ctx, func(info content.Info) error {
+		if _, ok := marked[info.Digest]; !ok {
+			unreferenced = append(unreferenced, info)
+		}
+		return nil
+	}); err != nil {
+		return GCStats{}, err
+	}
+	for _, info := range unreferenced {
+		if err := s.content.Delete(ctx, info.Digest); err != nil && !errdefs.IsNotFound(err) {
+			return stats, fmt.Errorf("failed to remove %s: %w", info.Digest, err)
+		}
+		stats.Removed = append(stats.Removed, info.Digest)
+		stats.Freed += info.Size
+	}
+
+	ingests, err := s.content.ListStatuses(ctx)
+	if err != nil {
+		return stats, err
+	}
+	for _, st := range ingests {
+		if time.Since(st.UpdatedAt) < staleIngest {
+			continue
+		}
+		if err := s.content.Abort(ctx, st.Ref); err != nil && !errdefs.IsNotFound(err) {
+			return stats, fmt.Errorf("failed to abort write %s: %w", st.Ref, err)
+		}
+		stats.Ingests++
+	}
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
+
+// Delete removes the blob dgst. A blob GC would keep, one reachable from an
+// image, from one of roots or from a lease, is only removed with force.
+func (s *Store) Delete(ctx context.Context, dgst digest.Digest, force bool, roots ...ocispec.Descriptor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.content.Info(ctx, dgst); err != nil {
+		return err
+	}
+	if !force {
+		marked, err := s.mark(ctx, roots)
+		if err != nil {
+			return err
+		}
+		if _, ok := marked[dgst]; ok {
+			return fmt.Errorf("blob %s is referenced: %w", dgst, errdefs.ErrFailedPrecondition)
+		}
+	}
+	return s.content.Delete(ctx, dgst)
+}
+
+// Referenced returns the digests of the blobs GC would keep
+func (s *Store) Referenced(ctx context.Context, roots ...ocispec.Descriptor) (map[digest.Digest]struct{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mark(ctx, roots)
+}
+
+// mark collects the blobs reachable from the images, roots and leases, the
+// caller holds s.mu
+func (s *Store) mark(ctx context.Context, roots []ocispec.Descriptor) (map[digest.Digest]struct{}, error) {
+	marked := map[digest.Digest]struct{}{}
+	for _, l := range s.leases {
+		for dgst := range l.resources {
+			marked[dgst] = struct{}{}
+		}
+	}
+	for _, img := range s.images {
+		roots = append(roots, img.Target)
+	}
+	handler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		marked[desc.Digest] = struct{}{}
+		children, err := images.Children(ctx, s.content, desc)
+		// manifests for other platforms are never fetched
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return children, err
+	})
+	if err := images.Walk(ctx, handler, roots...); err != nil {
+		return nil, fmt.Errorf("failed to mark referenced content: %w", err)
+	}
+	return marked, nil
+}
@@ -0,0 +1,124 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// writeStray puts a blob no image references into the content store
+func writeStray(t *testing.T, s *Store, data string) digest.Digest {
+	t.Helper()
+	desc := ocispec.Descriptor{MediaType: "application/octet-stream", Digest: digest.FromString(data), Size: int64(len(data))}
+	if err := content.WriteBlob(context.Background(), s.content, "stray-"+desc.Digest.String(), bytes.NewReader([]byte(data)), desc); err != nil {
+		t.Fatal(err)
+	}
+	return desc.Digest
+}
+
+func TestDeleteReferenced(t *testing.T) {
+	ctx := context.Background()
+	layout := t.TempDir()
+	img := writeLayout(t, layout, "v1", ocispec.ImageConfig{}, baseLayer)
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := s.Pull(ctx, "oci:"+layout+":v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := img.layers[0].Digest
+
+	if err := s.Delete(ctx, layer, false); !errdefs.IsFailedPrecondition(err) {
+		t.Fatalf("Delete of a layer of an image = %v, want a failed precondition", err)
+	}
+	stray := writeStray(t, s, "stray")
+	if err := s.Delete(ctx, stray, false); err != nil {
+		t.Errorf("Delete of an unreferenced blob: %v", err)
+	}
+	if err := s.Delete(ctx, stray, false); !errdefs.IsNotFound(err) {
+		t.Errorf("Delete of a missing blob = %v, want not found", err)
+	}
+
+	// the images of containers are roots even once the image is gone
+	s.mu.Lock()
+	delete(s.images, pulled.Name)
+	s.mu.Unlock()
+	if err := s.Delete(ctx, layer, false, pulled.Target); !errdefs.IsFailedPrecondition(err) {
+		t.Errorf("Delete of a layer of a container's image = %v, want a failed precondition", err)
+	}
+
+	// as are the blobs of pulls in flight
+	leased := writeStray(t, s, "leased")
+	lease := s.newLease()
+	s.addResource(lease, leased)
+	if err := s.Delete(ctx, leased, false); !errdefs.IsFailedPrecondition(err) {
+		t.Errorf("Delete of a leased blob = %v, want a failed precondition", err)
+	}
+	s.release(lease)
+
+	if err := s.Delete(ctx, layer, true, pulled.Target); err != nil {
+		t.Errorf("forced Delete: %v", err)
+	}
+	if _, err := s.content.Info(ctx, layer); !errdefs.IsNotFound(err) {
+		t.Errorf("forced Delete kept the layer: %v", err)
+	}
+}
+
+func TestGC(t *testing.T) {
+	ctx := context.Background()
+	layout := t.TempDir()
+	img := writeLayout(t, layout, "v1", ocispec.ImageConfig{}, baseLayer, appLayer)
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Pull(ctx, "oci:"+layout+":v1"); err != nil {
+		t.Fatal(err)
+	}
+	stray := writeStray(t, s, "stray")
+	leased := writeStray(t, s, "leased")
+	lease := s.newLease()
+	s.addResource(lease, leased)
+
+	referenced, err := s.Referenced(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dgst := range append([]digest.Digest{img.manifest.Digest, leased}, img.layers[0].Digest, img.layers[1].Digest) {
+		if _, ok := referenced[dgst]; !ok {
+			t.Errorf("%s is not referenced", dgst)
+		}
+	}
+	if _, ok := referenced[stray]; ok {
+		t.Error("stray blob is referenced")
+	}
+
+	stats, err := s.GC(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(stats.Removed, []digest.Digest{stray}) || stats.Freed != int64(len("stray")) {
+		t.Errorf("GC removed %v, %d bytes, want only the stray blob", stats.Removed, stats.Freed)
+	}
+
+	// once the pull is done its blobs are fair game
+	s.release(lease)
+	stats, err = s.GC(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(stats.Removed, []digest.Digest{leased}) {
+		t.Errorf("GC removed %v, want the blob whose lease was released", stats.Removed)
+	}
+	if _, err := s.content.Info(ctx, img.layers[1].Digest); err != nil {
+		t.Errorf("GC removed a layer of the image: %v", err)
+	}
+}
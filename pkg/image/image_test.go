@@ -0,0 +1,378 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"kettle/pkg/snapshot"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// file is an entry of a test layer, a directory when its name ends in a slash
+type file struct {
+	name    string
+	content string
+}
+
+// testImage is an image written to an OCI image layout
+type testImage struct {
+	dir      string
+	manifest ocispec.Descriptor
+	config   ocispec.Image
+	layers   []ocispec.Descriptor
+}
+
+// writeBlob stores data in the layout's blobs directory
+func writeBlob(t *testing.T, dir, mediaType string, data []byte) ocispec.Descriptor {
+	t.Helper()
+	dgst := digest.FromBytes(data)
+	path := filepath.Join(dir, ocispec.ImageBlobsDir, dgst.Algorithm().String(), dgst.Encoded())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(data))}
+}
+
+func writeJSONBlob(t *testing.T, dir, mediaType string, v any) ocispec.Descriptor {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeBlob(t, dir, mediaType, data)
+}
+
+// writeLayout writes an image of the given layers, each a gzipped tar, to an
+// OCI image layout in dir with its manifest tagged tag
+func writeLayout(t *testing.T, dir, tag string, imageConfig ocispec.ImageConfig, layers ...[]file) testImage {
+	t.Helper()
+	img := testImage{dir: dir}
+	platform := platforms.DefaultSpec()
+	img.config = ocispec.Image{
+		Platform: ocispec.Platform{OS: platform.OS, Architecture: platform.Architecture, Variant: platform.Variant},
+		Config:   imageConfig,
+		RootFS:   ocispec.RootFS{Type: "layers"},
+	}
+	for _, files := range layers {
+		var layer bytes.Buffer
+		tw := tar.NewWriter(&layer)
+		for _, f := range files {
+			hdr := &tar.Header{Name: f.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(f.content))}
+			if strings.HasSuffix(f.name, "/") {
+				hdr = &tar.Header{Name: f.name, Mode: 0755, Typeflag: tar.TypeDir}
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte(f.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		gz.Write(layer.Bytes())
+		gz.Close()
+		img.layers = append(img.layers, writeBlob(t, dir, ocispec.MediaTypeImageLayerGzip, compressed.Bytes()))
+		img.config.RootFS.DiffIDs = append(img.config.RootFS.DiffIDs, digest.FromBytes(layer.Bytes()))
+	}
+	configDesc := writeJSONBlob(t, dir, ocispec.MediaTypeImageConfig, img.config)
+	img.manifest = writeJSONBlob(t, dir, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    img.layers,
+	})
+
+	index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: ocispec.MediaTypeImageIndex}
+	if data, err := os.ReadFile(filepath.Join(dir, ocispec.ImageIndexFile)); err == nil {
+		if err := json.Unmarshal(data, &index); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tagged := img.manifest
+	tagged.Annotations = map[string]string{ocispec.AnnotationRefName: tag}
+	index.Manifests = append(index.Manifests, tagged)
+	data, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ocispec.ImageIndexFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	layout := []byte(`{"imageLayoutVersion":"` + ocispec.ImageLayoutVersion + `"}`)
+	if err := os.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), layout, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+var baseLayer = []file{
+	{"etc/", ""},
+	{"etc/passwd", "root:x:0:0:root:/root:/bin/sh\nnode:x:1000:1000::/home/node:/bin/sh\n"},
+	{"tmp/", ""},
+	{"tmp/scratch", "gone in the next layer"},
+}
+
+var appLayer = []file{
+	{"app/", ""},
+	{"app/main.js", "console.log('hi')"},
+	{"tmp/", ""},
+	{"tmp/.wh.scratch", ""},
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ ref, want string }{
+		{"alpine", "docker.io/library/alpine:latest"},
+		{"nginx:1.27", "docker.io/library/nginx:1.27"},
+		{"ghcr.io/org/app:v1", "ghcr.io/org/app:v1"},
+		{"oci:/images/app", "oci:/images/app"},
+		{"oci:/images/app:v1", "oci:/images/app:v1"},
+		{"oci:/images/../app/:v1", "oci:/app:v1"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.ref)
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+	// the daemon cannot know what a relative layout path was relative to
+	for _, ref := range []string{"UPPER", "oci:layout:v1", "oci:./layout"} {
+		if _, err := Normalize(ref); !errdefs.IsInvalidArgument(err) {
+			t.Errorf("Normalize(%q) = %v, want invalid argument", ref, err)
+		}
+	}
+}
+
+func TestAbsRef(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ ref, want string }{
+		{"alpine", "alpine"},
+		{"localhost:5000/app:v1", "localhost:5000/app:v1"},
+		{"oci:/images/app:v1", "oci:/images/app:v1"},
+		{"oci:layout:v1", "oci:" + filepath.Join(wd, "layout") + ":v1"},
+		{"oci:./layout", "oci:" + filepath.Join(wd, "layout")},
+	}
+	for _, tt := range tests {
+		if got, err := AbsRef(tt.ref); err != nil || got != tt.want {
+			t.Errorf("AbsRef(%q) = %q, %v, want %q", tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestPullLayout(t *testing.T) {
+	ctx := context.Background()
+	layout := t.TempDir()
+	img := writeLayout(t, layout, "v1", ocispec.ImageConfig{User: "node", Cmd: []string{"node", "/app/main.js"}}, baseLayer, appLayer)
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pulled, err := s.Pull(ctx, "oci:"+layout+":v1")
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if want := "oci:" + layout + ":v1"; pulled.Name != want {
+		t.Errorf("pulled %q, want %q", pulled.Name, want)
+	}
+	if pulled.Target.Digest != img.manifest.Digest {
+		t.Errorf("pulled %s, want the manifest %s", pulled.Target.Digest, img.manifest.Digest)
+	}
+	for _, desc := range append([]ocispec.Descriptor{img.manifest}, img.layers...) {
+		if _, err := s.Content().Info(ctx, desc.Digest); err != nil {
+			t.Errorf("blob %s not in the content store: %v", desc.Digest, err)
+		}
+	}
+	if got, err := s.Get("oci:" + layout + ":v1"); err != nil || got.Target.Digest != pulled.Target.Digest {
+		t.Errorf("Get = %v, %v", got, err)
+	}
+	if list := s.List(); len(list) != 1 {
+		t.Errorf("List = %v", list)
+	}
+	config, err := s.Config(ctx, pulled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Config.User != "node" || len(config.Config.Cmd) != 2 {
+		t.Errorf("config = %+v", config.Config)
+	}
+
+	// the index is kept across restarts of the store
+	reopened, err := NewStore(s.root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Get(pulled.Name); err != nil {
+		t.Errorf("image lost when the store was reopened: %v", err)
+	}
+
+	// a layout with a single image needs no tag
+	if _, err := s.Pull(ctx, "oci:"+layout); err != nil {
+		t.Errorf("Pull of an untagged layout: %v", err)
+	}
+	if _, err := s.Pull(ctx, "oci:"+layout+":v2"); !errdefs.IsNotFound(err) {
+		t.Errorf("Pull of a missing tag = %v, want not found", err)
+	}
+	writeLayout(t, layout, "v2", ocispec.ImageConfig{}, baseLayer)
+	if _, err := s.Pull(ctx, "oci:"+layout); !errdefs.IsInvalidArgument(err) {
+		t.Errorf("Pull of an untagged layout of two images = %v, want invalid argument", err)
+	}
+}
+
+func TestPullMissingBlob(t *testing.T) {
+	layout := t.TempDir()
+	img := writeLayout(t, layout, "v1", ocispec.ImageConfig{}, baseLayer)
+	blob := filepath.Join(layout, ocispec.ImageBlobsDir, "sha256", img.layers[0].Digest.Encoded())
+	if err := os.Remove(blob); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Pull(context.Background(), "oci:"+layout+":v1"); err == nil {
+		t.Fatal("Pull succeeded without a layer")
+	}
+	if _, err := s.Get("oci:" + layout + ":v1"); !errdefs.IsNotFound(err) {
+		t.Errorf("failed pull recorded the image: %v", err)
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("unpacking mounts snapshots, which needs root")
+	}
+	ctx := context.Background()
+	layout := t.TempDir()
+	writeLayout(t, layout, "v1", ocispec.ImageConfig{}, baseLayer, appLayer)
+	writeLayout(t, layout, "base", ocispec.ImageConfig{}, baseLayer)
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sn, err := snapshot.NewNative(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := s.Pull(ctx, "oci:"+layout+":v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := s.Unpack(ctx, app, sn)
+	if err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+	mounts, err := sn.View(ctx, "view", parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mount.WithReadonlyTempMount(ctx, mounts, func(root string) error {
+		if data, err := os.ReadFile(filepath.Join(root, "app", "main.js")); err != nil || string(data) != "console.log('hi')" {
+			t.Errorf("app/main.js = %q, %v", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "etc", "passwd")); err != nil {
+			t.Errorf("file of the lower layer missing: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "tmp", "scratch")); !os.IsNotExist(err) {
+			t.Errorf("whiteout did not remove tmp/scratch: %v", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the base layer is shared with the image built on it
+	base, err := s.Pull(ctx, "oci:"+layout+":base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseParent, err := s.Unpack(ctx, base, sn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := sn.Stat(ctx, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Parent != baseParent {
+		t.Errorf("app layer is on top of %q, want the base layer %q", info.Parent, baseParent)
+	}
+	again, err := s.Unpack(ctx, app, sn)
+	if err != nil || again != parent {
+		t.Errorf("second Unpack = %q, %v, want %q", again, err, parent)
+	}
+}
+
+func TestUnpackDiffIDMismatch(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("unpacking mounts snapshots, which needs root")
+	}
+	ctx := context.Background()
+	layout := t.TempDir()
+	img := writeLayout(t, layout, "v1", ocispec.ImageConfig{}, baseLayer)
+
+	// point the manifest at a config whose diff ID does not match the layer
+	config := img.config
+	config.RootFS.DiffIDs = []digest.Digest{digest.FromString("something else")}
+	configDesc := writeJSONBlob(t, layout, ocispec.MediaTypeImageConfig, config)
+	manifest := writeJSONBlob(t, layout, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    img.layers,
+	})
+	manifest.Annotations = map[string]string{ocispec.AnnotationRefName: "v1"}
+	data, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{manifest},
+	})
+	if err := os.WriteFile(filepath.Join(layout, ocispec.ImageIndexFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sn, err := snapshot.NewNative(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := s.Pull(ctx, "oci:"+layout+":v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Unpack(ctx, pulled, sn); err == nil {
+		t.Fatal("Unpack accepted a layer that does not match its diff ID")
+	}
+	if _, err := sn.Stat(ctx, config.RootFS.DiffIDs[0].String()); !errdefs.IsNotFound(err) {
+		t.Errorf("snapshot of the bad layer was committed: %v", err)
+	}
+}
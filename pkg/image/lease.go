@@ -0,0 +1,60 @@
+package image
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// Lease protects blobs from garbage collection while they are not referenced
+// by an image yet, for example during a pull. Leases only live in memory, a
+// pull does not survive the daemon either.
+type Lease struct {
+	ID        string
+	CreatedAt time.Time
+	resources map[digest.Digest]struct{}
+}
+
+// newLease starts a lease and holds it until release is called
+func (s *Store) newLease() *Lease {
+	b := make([]byte, 8)
+	rand.Read(b)
+	l := &Lease{
+		ID:        hex.EncodeToString(b),
+		CreatedAt: time.Now().UTC(),
+		resources: map[digest.Digest]struct{}{},
+	}
+	s.mu.Lock()
+	s.leases[l.ID] = l
+	s.mu.Unlock()
+	return l
+}
+
+// addResource puts dgst under the lease. It waits for a running garbage
+// collection, so a blob is either collected before it is leased or kept.
+func (s *Store) addResource(l *Lease, dgst digest.Digest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l.resources[dgst] = struct{}{}
+}
+
+// release drops the lease, its blobs are collectable again unless an image
+// references them by now
+func (s *Store) release(l *Lease) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.leases, l.ID)
+}
+
+// Leases returns the leases currently held
+func (s *Store) Leases() []Lease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leases := make([]Lease, 0, len(s.leases))
+	for _, l := range s.leases {
+		leases = append(leases, *l)
+	}
+	return leases
+}
@@ -0,0 +1,180 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// layoutPrefix marks a reference to an OCI image layout directory
+const layoutPrefix = "oci:"
+
+// Normalize returns the name ref is recorded under. The directory of an OCI
+// image layout has to be an absolute path, see AbsRef.
+func Normalize(ref string) (string, error) {
+	if strings.HasPrefix(ref, layoutPrefix) {
+		dir, tag := splitLayoutRef(ref)
+		if !filepath.IsAbs(dir) {
+			return "", fmt.Errorf("image layout path %q is not absolute: %w", dir, errdefs.ErrInvalidArgument)
+		}
+		return joinLayoutRef(filepath.Clean(dir), tag), nil
+	}
+	named, err := reference.ParseDockerRef(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %q: %v: %w", ref, err, errdefs.ErrInvalidArgument)
+	}
+	return named.String(), nil
+}
+
+// Pull fetches the image ref names, limited to the platform of this host, and
+// records it. Blobs already in the content store are not fetched again.
+func (s *Store) Pull(ctx context.Context, ref string) (Image, error) {
+	name, err := Normalize(ref)
+	if err != nil {
+		return Image{}, err
+	}
+	desc, fetcher, err := s.resolve(ctx, name)
+	if err != nil {
+		return Image{}, err
+	}
+
+	lease := s.newLease()
+	defer s.release(lease)
+	fetch := remotes.FetchHandler(s.content, fetcher)
+	// the children of a manifest are only known once it has been fetched,
+	// so the fetch handler has to run first. Blobs are leased before they
+	// are fetched, or found to be there already, so GC keeps them.
+	handler := images.Handlers(
+		images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+			s.addResource(lease, desc.Digest)
+			return fetch(ctx, desc)
+		}),
+		images.LimitManifests(images.FilterPlatforms(images.ChildrenHandler(s.content), s.platform), s.platform, 1),
+	)
+	if err := images.Dispatch(ctx, handler, nil, desc); err != nil {
+		return Image{}, fmt.Errorf("failed to pull %s: %w", name, err)
+	}
+
+	img := Image{Name: name, Target: desc, CreatedAt: time.Now().UTC()}
+	if err := s.put(img); err != nil {
+		return Image{}, err
+	}
+	return img, nil
+}
+
+// resolve finds the descriptor name points at and a fetcher for its content
+func (s *Store) resolve(ctx context.Context, name string) (ocispec.Descriptor, remotes.Fetcher, error) {
+	if strings.HasPrefix(name, layoutPrefix) {
+		dir, tag := splitLayoutRef(name)
+		f := layoutFetcher(dir)
+		desc, err := f.resolve(tag)
+		if err != nil {
+			return ocispec.Descriptor{}, nil, err
+		}
+		return desc, f, nil
+	}
+	resolver := docker.NewResolver(docker.ResolverOptions{
+		// plain HTTP is fine for a registry on this host, handy for testing
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithAuthorizer(docker.NewDockerAuthorizer()),
+			docker.WithPlainHTTP(docker.MatchLocalhost),
+		),
+	})
+	resolved, desc, err := resolver.Resolve(ctx, name)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve %s: %w", name, err)
+	}
+	fetcher, err := resolver.Fetcher(ctx, resolved)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	return desc, fetcher, nil
+}
+
+// splitLayoutRef splits oci:<path>[:<tag>] into the path and tag
+func splitLayoutRef(ref string) (dir, tag string) {
+	ref = strings.TrimPrefix(ref, layoutPrefix)
+	i := strings.LastIndex(ref, ":")
+	if i <= strings.LastIndex(ref, "/") {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// AbsRef makes the directory of an OCI image layout reference absolute,
+// relative to the working directory of the caller rather than of the daemon
+// the reference is sent to. Other references are returned as they are.
+func AbsRef(ref string) (string, error) {
+	if !strings.HasPrefix(ref, layoutPrefix) {
+		return ref, nil
+	}
+	dir, tag := splitLayoutRef(ref)
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return joinLayoutRef(abs, tag), nil
+}
+
+func joinLayoutRef(dir, tag string) string {
+	if tag == "" {
+		return layoutPrefix + dir
+	}
+	return layoutPrefix + dir + ":" + tag
+}
+
+// layoutFetcher reads blobs out of an OCI image layout directory
+type layoutFetcher string
+
+func (f layoutFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %w", desc.Digest, errdefs.ErrInvalidArgument)
+	}
+	blob, err := os.Open(filepath.Join(string(f), ocispec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("blob %s: %w", desc.Digest, errdefs.ErrNotFound)
+		}
+		return nil, err
+	}
+	return blob, nil
+}
+
+// resolve picks the manifest tagged tag out of the layout's index. Without a
+// tag the index must hold exactly one manifest.
+func (f layoutFetcher) resolve(tag string) (ocispec.Descriptor, error) {
+	data, err := os.ReadFile(filepath.Join(string(f), ocispec.ImageIndexFile))
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to read image layout: %w", err)
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse image layout index: %w", err)
+	}
+	if tag == "" {
+		if len(index.Manifests) != 1 {
+			return ocispec.Descriptor{}, fmt.Errorf("image layout %s holds %d images, name one by tag: %w", string(f), len(index.Manifests), errdefs.ErrInvalidArgument)
+		}
+		return index.Manifests[0], nil
+	}
+	for _, desc := range index.Manifests {
+		name := desc.Annotations[ocispec.AnnotationRefName]
+		// the annotation may hold a full reference rather than just the tag
+		if name == tag || strings.HasSuffix(name, ":"+tag) {
+			return desc, nil
+		}
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("tag %q in image layout %s: %w", tag, string(f), errdefs.ErrNotFound)
+}
@@ -0,0 +1,168 @@
+// Package image pulls OCI images and unpacks them into the snapshots container
+// root filesystems are prepared from.
+//
+// Blobs are kept in a content store under <root>/content, laid out by
+// digest, and the images pointing into it are recorded in <root>/images.json.
+// Writes go to an ingest area first and are only committed once their digest
+// checks out; an interrupted write is resumed by the next pull of the blob.
+// Blobs no image references are removed by GC, those of pulls in flight are
+// protected by leases.
+//
+// References are either registry references, normalized the way docker does
+// ("alpine" is docker.io/library/alpine:latest), or OCI image layout
+// directories written as
+//
+//	oci:<path>[:<tag>]
+//
+// where the tag is matched against the org.opencontainers.image.ref.name
+// annotation of the layout's index.
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"kettle/pkg/content"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Image is a pulled image: a name and the index or manifest it resolved to
+type Image struct {
+	Name      string             `json:"name"`
+	Target    ocispec.Descriptor `json:"target"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Store keeps pulled images and their content
+type Store struct {
+	// mu guards the images and leases, and is held for the whole of a
+	// garbage collection
+	mu       sync.Mutex
+	root     string
+	content  *content.Store
+	images   map[string]Image
+	leases   map[string]*Lease
+	platform platforms.MatchComparer
+}
+
+// NewStore opens the image store under root, creating it if needed
+func NewStore(root string) (*Store, error) {
+	cs, err := content.NewStore(filepath.Join(root, "content"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open content store: %w", err)
+	}
+	s := &Store{
+		root:     root,
+		content:  cs,
+		images:   map[string]Image{},
+		leases:   map[string]*Lease{},
+		platform: platforms.Default(),
+	}
+	data, err := os.ReadFile(s.indexPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read image index: %w", err)
+	}
+	if len(data) > 0 {
+		var images []Image
+		if err := json.Unmarshal(data, &images); err != nil {
+			return nil, fmt.Errorf("failed to parse image index: %w", err)
+		}
+		for _, img := range images {
+			s.images[img.Name] = img
+		}
+	}
+	return s, nil
+}
+
+// Content is the store the image blobs are kept in
+func (s *Store) Content() *content.Store {
+	return s.content
+}
+
+// Get returns the image recorded under ref
+func (s *Store) Get(ref string) (Image, error) {
+	name, err := Normalize(ref)
+	if err != nil {
+		return Image{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	img, ok := s.images[name]
+	if !ok {
+		return Image{}, fmt.Errorf("image %q: %w", name, errdefs.ErrNotFound)
+	}
+	return img, nil
+}
+
+// List returns every image, sorted by name
+func (s *Store) List() []Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	images := make([]Image, 0, len(s.images))
+	for _, img := range s.images {
+		images = append(images, img)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	return images
+}
+
+// put records img, replacing any image of the same name
+func (s *Store) put(img Image) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.images[img.Name]
+	s.images[img.Name] = img
+	if err := s.writeIndex(); err != nil {
+		if existed {
+			s.images[img.Name] = old
+		} else {
+			delete(s.images, img.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Store) indexPath() string {
+	return filepath.Join(s.root, "images.json")
+}
+
+// writeIndex replaces images.json by renaming a fully written file over it
+func (s *Store) writeIndex() error {
+	images := make([]Image, 0, len(s.images))
+	for _, img := range s.images {
+		images = append(images, img)
+	}
+	data, err := json.MarshalIndent(images, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.root, ".images-*")
+	if err != nil {
+		return fmt.Errorf("failed to write image index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write image index: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write image index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write image index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.indexPath()); err != nil {
+		return fmt.Errorf("failed to write image index: %w", err)
+	}
+	return nil
+}
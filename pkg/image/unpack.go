@@ -0,0 +1,132 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"kettle/pkg/snapshot"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/pkg/archive"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Config returns the image configuration for the platform of this host
+func (s *Store) Config(ctx context.Context, img Image) (ocispec.Image, error) {
+	desc, err := images.Config(ctx, s.content, img.Target, s.platform)
+	if err != nil {
+		return ocispec.Image{}, fmt.Errorf("failed to find config of %s: %w", img.Name, err)
+	}
+	data, err := content.ReadBlob(ctx, s.content, desc)
+	if err != nil {
+		return ocispec.Image{}, fmt.Errorf("failed to read config of %s: %w", img.Name, err)
+	}
+	var config ocispec.Image
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ocispec.Image{}, fmt.Errorf("failed to parse config of %s: %w", img.Name, err)
+	}
+	return config, nil
+}
+
+// Unpack turns the layers of img into a chain of committed snapshots in sn,
+// one per layer, and returns the name of the last one for container root
+// filesystems to be prepared on top of. Snapshots are named by the chain ID
+// of their layer, layers already unpacked by an earlier image are shared.
+//
+// Layers may be uncompressed, gzip or zstd compressed tars; whiteouts remove
+// what lower layers put in place. Every layer is checked against the diff ID
+// the image config records for it.
+func (s *Store) Unpack(ctx context.Context, img Image, sn snapshot.Snapshotter) (string, error) {
+	manifest, err := images.Manifest(ctx, s.content, img.Target, s.platform)
+	if err != nil {
+		return "", fmt.Errorf("failed to find manifest of %s: %w", img.Name, err)
+	}
+	config, err := s.Config(ctx, img)
+	if err != nil {
+		return "", err
+	}
+	diffIDs := config.RootFS.DiffIDs
+	if len(diffIDs) != len(manifest.Layers) {
+		return "", fmt.Errorf("image %s has %d layers but %d diff IDs", img.Name, len(manifest.Layers), len(diffIDs))
+	}
+	parent := ""
+	for i, layer := range manifest.Layers {
+		chainID := identity.ChainID(diffIDs[:i+1]).String()
+		if _, err := sn.Stat(ctx, chainID); err == nil {
+			parent = chainID
+			continue
+		}
+		if err := s.unpackLayer(ctx, sn, layer, diffIDs[i], chainID, parent); err != nil {
+			return "", fmt.Errorf("failed to apply layer %s of %s: %w", layer.Digest, img.Name, err)
+		}
+		parent = chainID
+	}
+	return parent, nil
+}
+
+// unpackLayer applies layer on top of the snapshot parent and commits the
+// result as chainID
+func (s *Store) unpackLayer(ctx context.Context, sn snapshot.Snapshotter, layer ocispec.Descriptor, diffID digest.Digest, chainID, parent string) (err error) {
+	// unpacks of the same layer may race, each works in a snapshot of its own
+	key := fmt.Sprintf("extract-%d-%s", time.Now().UnixNano(), chainID)
+	mounts, err := sn.Prepare(ctx, key, parent)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			sn.Remove(ctx, key)
+		}
+	}()
+	if err := mount.WithTempMount(ctx, mounts, func(root string) error {
+		return s.applyLayer(ctx, layer, diffID, root)
+	}); err != nil {
+		return err
+	}
+	if err := sn.Commit(ctx, chainID, key); err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			// another unpack got there first, its snapshot is just as good
+			sn.Remove(ctx, key)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Store) applyLayer(ctx context.Context, layer ocispec.Descriptor, diffID digest.Digest, dir string) error {
+	ra, err := s.content.ReaderAt(ctx, layer)
+	if err != nil {
+		return err
+	}
+	defer ra.Close()
+	ds, err := compression.DecompressStream(content.NewReader(ra))
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	digester := diffID.Algorithm().Digester()
+	r := io.TeeReader(ds, digester.Hash())
+	if _, err := archive.Apply(ctx, dir, r); err != nil {
+		return err
+	}
+	// the tar reader stops at the end-of-archive marker, the padding after
+	// it still counts towards the diff ID
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return err
+	}
+	if got := digester.Digest(); got != diffID {
+		return fmt.Errorf("layer content is %s, expected %s", got, diffID)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lines returns the content of the records, partial records joined into the
+// line they are part of
+func lines(entries []Entry) []string {
+	var out []string
+	var line []byte
+	for _, e := range entries {
+		line = append(line, e.Line...)
+		if !e.Partial {
+			out = append(out, string(line))
+			line = nil
+		}
+	}
+	return out
+}
+
+func numbered(from, to int) []string {
+	var out []string
+	for i := from; i <= to; i++ {
+		out = append(out, fmt.Sprintf("line %d", i))
+	}
+	return out
+}
+
+func TestWriterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container.log")
+	const maxSize, maxFiles = 200, 2
+	w, err := NewWriter(path, maxSize, maxFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := strings.Join(numbered(1, 30), "\n") + "\n"
+	if err := w.Copy(Stdout, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		fi, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Size() > maxSize {
+			t.Errorf("%s is %d bytes, over the %d limit", filepath.Base(name), fi.Size(), maxSize)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("more than %d rotated files are kept: %v", maxFiles, err)
+	}
+
+	entries, offset, err := Read(path, maxFiles, ReadOptions{Tail: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := lines(entries)
+	if len(got) == 0 || len(got) == 30 {
+		t.Fatalf("read %d lines, want the newest ones without the rotated away", len(got))
+	}
+	if want := numbered(31-len(got), 30); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("read %v, want %v", got, want)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() != offset {
+		t.Errorf("offset = %d, want the size of the current file %v", offset, fi.Size())
+	}
+	for _, e := range entries {
+		if e.Stream != Stdout {
+			t.Errorf("entry of stream %q", e.Stream)
+		}
+	}
+}
+
+func TestWriterLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container.log")
+	w, err := NewWriter(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := strings.Repeat("x", maxLineSize*2+10)
+	if err := w.Copy(Stderr, strings.NewReader("short\n"+long+"\nlast")); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	entries, _, err := Read(path, 0, ReadOptions{Tail: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("got %d records, want 5: short, 3 chunks of the long line and last", len(entries))
+	}
+	for i, partial := range []bool{false, true, true, false, false} {
+		if entries[i].Partial != partial {
+			t.Errorf("record %d partial = %v, want %v", i, entries[i].Partial, partial)
+		}
+	}
+	if got := lines(entries); len(got) != 3 || got[0] != "short" || got[1] != long || got[2] != "last" {
+		t.Errorf("lines do not survive the split")
+	}
+
+	// the partial records of a line count as one line of the tail
+	entries, _, err = Read(path, 0, ReadOptions{Tail: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lines(entries); len(got) != 2 || got[0] != long || got[1] != "last" {
+		t.Errorf("tail of 2 returned %d lines", len(got))
+	}
+}
+
+func TestTail(t *testing.T) {
+	entries := []Entry{
+		{Line: []byte("a")},
+		{Line: []byte("b"), Partial: true},
+		{Line: []byte("c")},
+		{Line: []byte("d")},
+	}
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{1, "d"},
+		{2, "bc,d"},
+		{3, "a,bc,d"},
+		{10, "a,bc,d"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(lines(tail(entries, tt.n)), ","); got != tt.want {
+			t.Errorf("tail(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestReadSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container.log")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []string
+	for i := 0; i < 4; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute).Format(time.RFC3339Nano)
+		records = append(records, fmt.Sprintf("%s stdout F line %d\n", ts, i))
+	}
+	// garbage and a record cut short by a crash are skipped
+	records = append(records, "not a record\n", start.Format(time.RFC3339Nano)+" stdout F cut")
+	if err := os.WriteFile(path, []byte(strings.Join(records, "")), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := Read(path, 0, ReadOptions{Tail: -1, Since: start.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(lines(entries), ","); got != "line 2,line 3" {
+		t.Errorf("read %q since the third record", got)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container.log")
+	w, err := NewWriter(path, 300, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Copy(Stdout, strings.NewReader("line 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	_, offset, err := Read(path, 1, ReadOptions{Tail: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got := make(chan string, 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, offset, nil, func(e Entry) error {
+			got <- string(e.Line)
+			return nil
+		})
+	}()
+
+	// enough lines to rotate the file while it is followed, slow enough for
+	// Follow to see every file before the next rotation
+	want := numbered(2, 10)
+	for _, line := range want {
+		if err := w.Copy(Stdout, strings.NewReader(line+"\n")); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	for _, line := range want {
+		select {
+		case l := <-got:
+			if l != line {
+				t.Fatalf("followed %q, want %q", l, line)
+			}
+		case <-ctx.Done():
+			t.Fatalf("did not follow %q", line)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow: %v", err)
+	}
+}
+
+func TestFollowStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container.log")
+	w, err := NewWriter(path, 1<<20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.Copy(Stdout, strings.NewReader("line 1\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stop := make(chan struct{})
+	var got []string
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, 0, stop, func(e Entry) error {
+			got = append(got, string(e.Line))
+			return nil
+		})
+	}()
+	// what the writer got in before it stopped is still followed
+	if err := w.Copy(Stdout, strings.NewReader("line 2\n")); err != nil {
+		t.Fatal(err)
+	}
+	close(stop)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Follow: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Follow did not return once stopped")
+	}
+	if want := []string{"line 1", "line 2"}; !slices.Equal(got, want) {
+		t.Errorf("followed %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Entry is a single parsed log record
+type Entry struct {
+	Timestamp time.Time
+	Stream    string
+	// Partial is set when Line is only a chunk of a longer line
+	Partial bool
+	Line    []byte
+}
+
+// ParseRecord parses one CRI log line without its trailing newline
+func ParseRecord(record []byte) (Entry, error) {
+	fields := bytes.SplitN(record, []byte{' '}, 4)
+	if len(fields) < 3 {
+		return Entry{}, fmt.Errorf("malformed log record %q", record)
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(fields[0]))
+	if err != nil {
+		return Entry{}, fmt.Errorf("malformed log timestamp: %w", err)
+	}
+	e := Entry{
+		Timestamp: t,
+		Stream:    string(fields[1]),
+		Partial:   string(fields[2]) == tagPartial,
+	}
+	if len(fields) == 4 {
+		e.Line = fields[3]
+	}
+	return e, nil
+}
+
+// ReadOptions select which of the existing records Read returns
+type ReadOptions struct {
+	// Tail keeps only the last Tail lines, all of them when negative
+	Tail int
+	// Since drops records written before it
+	Since time.Time
+}
+
+// Read returns the records of path and its rotated files, oldest first, along
+// with the offset in path up to which records were read. Passing that offset
+// to Follow continues exactly where Read stopped.
+func Read(path string, maxFiles int, opts ReadOptions) ([]Entry, int64, error) {
+	var entries []Entry
+	var offset int64
+	for i := maxFiles; i >= 0; i-- {
+		name := path
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", path, i)
+		}
+		f, err := os.Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		n, err := scan(f, func(e Entry) {
+			if e.Timestamp.Before(opts.Since) {
+				return
+			}
+			entries = append(entries, e)
+		})
+		f.Close()
+		if err != nil {
+			return nil, 0, err
+		}
+		if i == 0 {
+			offset = n
+		}
+	}
+	if opts.Tail >= 0 {
+		entries = tail(entries, opts.Tail)
+	}
+	return entries, offset, nil
+}
+
+// tail keeps the records that make up the last n lines, counting a run
+// of partial records and the full record ending it as one line
+func tail(entries []Entry, n int) []Entry {
+	lines := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].Partial {
+			if lines == n {
+				return entries[i+1:]
+			}
+			lines++
+		}
+	}
+	return entries
+}
+
+// scan calls fn for every complete record in r and returns how many bytes
+// those records took up
+func scan(r io.Reader, fn func(Entry)) (int64, error) {
+	br := bufio.NewReader(r)
+	var n int64
+	for {
+		record, err := br.ReadBytes('\n')
+		if len(record) > 0 && record[len(record)-1] == '\n' {
+			n += int64(len(record))
+			if e, perr := ParseRecord(record[:len(record)-1]); perr == nil {
+				fn(e)
+			}
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// followInterval is how often Follow looks for records at the end of the file
+const followInterval = 250 * time.Millisecond
+
+// Follow calls fn for every record written to path from offset on, following
+// the file across rotations, until ctx is done. Closing stop tells Follow
+// nothing is going to write to path any more, it then returns once it found
+// no new records for a whole interval.
+func Follow(ctx context.Context, path string, offset int64, stop <-chan struct{}, fn func(Entry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(f)
+	var pending []byte
+	// draining is set once f was found rotated away, what the writer added
+	// to it before the rename is read before moving on
+	draining := false
+	// idle is set when an interval passed without new records
+	idle := false
+	for {
+		chunk, err := br.ReadBytes('\n')
+		pending = append(pending, chunk...)
+		if len(chunk) > 0 {
+			idle = false
+		}
+		if err == nil {
+			if e, perr := ParseRecord(pending[:len(pending)-1]); perr == nil {
+				if err := fn(e); err != nil {
+					return err
+				}
+			}
+			pending = pending[:0]
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		// at the end of the file, it either grows or gets rotated away
+		if rotated(f, path) {
+			if !draining {
+				draining = true
+				continue
+			}
+			next, err := os.Open(path)
+			if err == nil {
+				f.Close()
+				f = next
+				br.Reset(f)
+				// a record cut short stays that way
+				pending = pending[:0]
+				draining = false
+				continue
+			}
+		}
+		if idle && isClosed(stop) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followInterval):
+		}
+		idle = true
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// rotated tells whether path no longer refers to the open file f
+func rotated(f *os.File, path string) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	open, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(open, current)
+}
@@ -0,0 +1,146 @@
+// Package logs reads and writes container logs in the CRI log format.
+//
+// Every line of container output becomes one record of the form
+//
+//	<RFC3339Nano timestamp> <stream> <tag> <content>
+//
+// where stream is stdout or stderr and tag is F for a full line or P for a
+// chunk of a line that was too long to be kept in one record.
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+
+	tagFull    = "F"
+	tagPartial = "P"
+
+	// maxLineSize splits longer lines into partial records
+	maxLineSize = 16 * 1024
+)
+
+// Writer appends CRI log records to a file and rotates it once it grows
+// beyond MaxSize, keeping at most MaxFiles rotated files next to it as
+// path.1 (newest) to path.N (oldest).
+type Writer struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// NewWriter opens path for appending. A maxSize of 0 disables rotation.
+func NewWriter(path string, maxSize int64, maxFiles int) (*Writer, error) {
+	w := &Writer{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// Copy reads r line by line until EOF and logs every line as stream
+func (w *Writer) Copy(stream string, r io.Reader) error {
+	br := bufio.NewReaderSize(r, maxLineSize)
+	for {
+		line, isPrefix, err := br.ReadLine()
+		if len(line) > 0 || (err == nil && !isPrefix) {
+			tag := tagFull
+			if isPrefix {
+				tag = tagPartial
+			}
+			if werr := w.writeRecord(time.Now(), stream, tag, line); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (w *Writer) writeRecord(t time.Time, stream, tag string, line []byte) error {
+	var buf bytes.Buffer
+	buf.WriteString(t.UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(' ')
+	buf.WriteString(stream)
+	buf.WriteByte(' ')
+	buf.WriteString(tag)
+	buf.WriteByte(' ')
+	buf.Write(line)
+	buf.WriteByte('\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return fmt.Errorf("log writer is closed")
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(buf.Len()) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(buf.Bytes())
+	w.size += int64(n)
+	return err
+}
+
+// rotate shifts path.N-1 to path.N down to path to path.1 and starts a new file
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+	if w.maxFiles <= 0 {
+		os.Remove(w.path)
+		return w.open()
+	}
+	os.Remove(fmt.Sprintf("%s.%d", w.path, w.maxFiles))
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return w.open()
+}
+
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
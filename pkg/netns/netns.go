@@ -0,0 +1,106 @@
+// Package netns creates named network namespaces.
+//
+// A named namespace is a new network namespace bind mounted onto a file, the
+// way "ip netns add" does it, so that it lives on without a process in it and
+// can be handed to runc and to CNI plugins by path. Its loopback interface is
+// brought up, the CNI plugins take care of everything else.
+package netns
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultDir is where "ip netns" looks for named namespaces
+const DefaultDir = "/run/netns"
+
+// Create makes a new network namespace named name in dir and returns its
+// path. A namespace already there is kept.
+func Create(dir, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	if Exists(path) {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create netns directory: %w", err)
+	}
+	// a file without a namespace on it is left over from a failed create
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to remove stale netns file: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return "", fmt.Errorf("failed to create netns file: %w", err)
+	}
+	f.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		// the thread is left in the new namespace, the runtime throws it
+		// away once this goroutine exits without unlocking it
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			errCh <- fmt.Errorf("failed to unshare network namespace: %w", err)
+			return
+		}
+		if err := setLinkUp("lo"); err != nil {
+			errCh <- fmt.Errorf("failed to bring up loopback: %w", err)
+			return
+		}
+		thread := fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid())
+		if err := unix.Mount(thread, path, "none", unix.MS_BIND, ""); err != nil {
+			errCh <- fmt.Errorf("failed to bind mount network namespace: %w", err)
+			return
+		}
+		errCh <- nil
+	}()
+	if err := <-errCh; err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// setLinkUp brings up the interface name of the current thread's network namespace
+func setLinkUp(name string) error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return err
+	}
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr)
+}
+
+// Exists tells whether a network namespace is mounted at path
+func Exists(path string) bool {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return false
+	}
+	return st.Type == unix.NSFS_MAGIC
+}
+
+// Remove unmounts the network namespace at path and removes the file. A
+// namespace that is already gone is fine.
+func Remove(path string) error {
+	if err := unix.Unmount(path, unix.MNT_DETACH); err != nil && !errors.Is(err, unix.EINVAL) && !errors.Is(err, unix.ENOENT) {
+		return fmt.Errorf("failed to unmount network namespace: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove network namespace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"kettle/pkg/archive"
+
+	"github.com/moby/sys/user"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// cpuPeriod is the CFS period CPU counts are converted against
+const cpuPeriod = 100000
+
+// AnnotationStopSignal carries the image's StopSignal in a spec
+const AnnotationStopSignal = "org.opencontainers.image.stopSignal"
+
+// defaultPath is set for processes whose environment has no PATH
+const defaultPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// WithProcessArgs replaces the command the container runs
+func WithProcessArgs(args ...string) SpecOpts {
+	return func(s *specs.Spec) error {
+		s.Process.Args = args
+		return nil
+	}
+}
+
+// WithTTY runs the process on a terminal or not
+func WithTTY(terminal bool) SpecOpts {
+	return func(s *specs.Spec) error {
+		s.Process.Terminal = terminal
+		return nil
+	}
+}
+
+// WithHostname sets the container's hostname
+func WithHostname(name string) SpecOpts {
+	return func(s *specs.Spec) error {
+		s.Hostname = name
+		return nil
+	}
+}
+
+// WithProcessCwd sets the working directory of the process
+func WithProcessCwd(cwd string) SpecOpts {
+	return func(s *specs.Spec) error {
+		s.Process.Cwd = cwd
+		return nil
+	}
+}
+
+// WithProcessEnv replaces the whole environment of the process
+func WithProcessEnv(env []string) SpecOpts {
+	return func(s *specs.Spec) error {
+		s.Process.Env = env
+		return nil
+	}
+}
+
+// WithRootfsReadonly mounts the container's root filesystem read-only or not
+func WithRootfsReadonly(readonly bool) SpecOpts {
+	return func(s *specs.Spec) error {
+		s.Root.Readonly = readonly
+		return nil
+	}
+}
+
+// WithEnv sets KEY=VALUE pairs, replacing earlier values of the same variables
+func WithEnv(env []string) SpecOpts {
+	return func(s *specs.Spec) error {
+		for _, kv := range env {
+			key, _, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", kv)
+			}
+			replaced := false
+			for i, existing := range s.Process.Env {
+				if strings.HasPrefix(existing, key+"=") {
+					s.Process.Env[i] = kv
+					replaced = true
+				}
+			}
+			if !replaced {
+				s.Process.Env = append(s.Process.Env, kv)
+			}
+		}
+		return nil
+	}
+}
+
+// WithMounts adds mounts, replacing those of the default spec that have the
+// same destination
+func WithMounts(mounts []specs.Mount) SpecOpts {
+	return func(s *specs.Spec) error {
+		for _, m := range mounts {
+			if m.Destination == "" {
+				return fmt.Errorf("mount of %q has no destination", m.Source)
+			}
+			replaced := false
+			for i, existing := range s.Mounts {
+				if existing.Destination == m.Destination {
+					s.Mounts[i] = m
+					replaced = true
+				}
+			}
+			if !replaced {
+				s.Mounts = append(s.Mounts, m)
+			}
+		}
+		return nil
+	}
+}
+
+// WithUser runs the process as uid[:gid]. Names cannot be resolved without the
+// image, only numeric IDs are accepted, see WithRootfsUser for those.
+func WithUser(user string) SpecOpts {
+	return func(s *specs.Spec) error {
+		uidPart, gidPart, hasGid := strings.Cut(user, ":")
+		uid, err := strconv.ParseUint(uidPart, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid user %q, expected uid[:gid]", user)
+		}
+		gid := uid
+		if hasGid {
+			if gid, err = strconv.ParseUint(gidPart, 10, 32); err != nil {
+				return fmt.Errorf("invalid user %q, expected uid[:gid]", user)
+			}
+		}
+		s.Process.User = specs.User{UID: uint32(uid), GID: uint32(gid)}
+		return nil
+	}
+}
+
+// WithRootfsUser runs the process as user[:group], either of which can be a
+// name looked up in the /etc/passwd and /etc/group of rootfs or a numeric ID.
+// Without a group the user's primary group is used, 0 for a numeric uid
+// missing from /etc/passwd, and the groups listing the user as a member become
+// its additional groups.
+func WithRootfsUser(userSpec, rootfs string) SpecOpts {
+	return func(s *specs.Spec) error {
+		passwd, err := archive.OpenInRoot(rootfs, "/etc/passwd")
+		if err == nil {
+			defer passwd.Close()
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		group, err := archive.OpenInRoot(rootfs, "/etc/group")
+		if err == nil {
+			defer group.Close()
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		// a nil *os.File has to become a nil io.Reader for the files to be
+		// treated as missing
+		var passwdReader, groupReader io.Reader
+		if passwd != nil {
+			passwdReader = passwd
+		}
+		if group != nil {
+			groupReader = group
+		}
+		execUser, err := user.GetExecUser(userSpec, nil, passwdReader, groupReader)
+		if err != nil {
+			return fmt.Errorf("invalid user %q: %w", userSpec, err)
+		}
+		s.Process.User = specs.User{UID: uint32(execUser.Uid), GID: uint32(execUser.Gid)}
+		s.Process.User.AdditionalGids = nil
+		for _, gid := range execUser.Sgids {
+			s.Process.User.AdditionalGids = append(s.Process.User.AdditionalGids, uint32(gid))
+		}
+		return nil
+	}
+}
+
+// WithResources replaces the cgroup limits, keeping the device rules of the spec
+func WithResources(resources *specs.LinuxResources) SpecOpts {
+	return func(s *specs.Spec) error {
+		if s.Linux.Resources != nil && resources.Devices == nil {
+			resources.Devices = s.Linux.Resources.Devices
+		}
+		s.Linux.Resources = resources
+		return nil
+	}
+}
+
+// WithCPUs limits the container to cpus CPUs worth of time
+func WithCPUs(cpus float64) SpecOpts {
+	return func(s *specs.Spec) error {
+		if cpus <= 0 {
+			return fmt.Errorf("invalid cpus %v", cpus)
+		}
+		period := uint64(cpuPeriod)
+		quota := int64(cpus * cpuPeriod)
+		c := cpu(s)
+		c.Period = &period
+		c.Quota = &quota
+		return nil
+	}
+}
+
+// WithCPUShares sets the container's share of CPU time relative to others,
+// runc turns it into a cpu.weight on cgroup v2
+func WithCPUShares(shares uint64) SpecOpts {
+	return func(s *specs.Spec) error {
+		if shares < 2 || shares > 262144 {
+			return fmt.Errorf("invalid cpu shares %d, expected 2 to 262144", shares)
+		}
+		cpu(s).Shares = &shares
+		return nil
+	}
+}
+
+// WithCPUSet pins the container to the cpus and memory nodes mems, in list
+// format such as 0-2,4. Empty lists are left alone.
+func WithCPUSet(cpus, mems string) SpecOpts {
+	return func(s *specs.Spec) error {
+		if cpus != "" {
+			cpu(s).Cpus = cpus
+		}
+		if mems != "" {
+			cpu(s).Mems = mems
+		}
+		return nil
+	}
+}
+
+// WithMemoryLimit limits the memory of the container to limit bytes
+func WithMemoryLimit(limit int64) SpecOpts {
+	return func(s *specs.Spec) error {
+		if limit <= 0 {
+			return fmt.Errorf("invalid memory limit %d", limit)
+		}
+		memory(s).Limit = &limit
+		return nil
+	}
+}
+
+// WithMemorySwap limits memory and swap together to swap bytes, -1 lifts the
+// limit on swap
+func WithMemorySwap(swap int64) SpecOpts {
+	return func(s *specs.Spec) error {
+		if swap <= 0 && swap != -1 {
+			return fmt.Errorf("invalid memory swap limit %d", swap)
+		}
+		memory(s).Swap = &swap
+		return nil
+	}
+}
+
+// WithPidsLimit limits the number of processes in the container, -1 lifts the
+// limit
+func WithPidsLimit(limit int64) SpecOpts {
+	return func(s *specs.Spec) error {
+		if limit <= 0 && limit != -1 {
+			return fmt.Errorf("invalid pids limit %d", limit)
+		}
+		resources(s).Pids = &specs.LinuxPids{Limit: limit}
+		return nil
+	}
+}
+
+// WithIOWeight sets the container's share of block IO relative to others
+func WithIOWeight(weight uint16) SpecOpts {
+	return func(s *specs.Spec) error {
+		if weight < 10 || weight > 1000 {
+			return fmt.Errorf("invalid io weight %d, expected 10 to 1000", weight)
+		}
+		r := resources(s)
+		if r.BlockIO == nil {
+			r.BlockIO = &specs.LinuxBlockIO{}
+		}
+		r.BlockIO.Weight = &weight
+		return nil
+	}
+}
+
+// WithResourceUpdates changes the cgroup limits update sets and keeps the
+// others, unlike WithResources which replaces them all
+func WithResourceUpdates(update *specs.LinuxResources) SpecOpts {
+	return func(s *specs.Spec) error {
+		if update == nil {
+			return nil
+		}
+		r := resources(s)
+		if u := update.CPU; u != nil {
+			c := cpu(s)
+			if u.Shares != nil {
+				c.Shares = u.Shares
+			}
+			if u.Quota != nil {
+				c.Quota = u.Quota
+			}
+			if u.Period != nil {
+				c.Period = u.Period
+			}
+			if u.Cpus != "" {
+				c.Cpus = u.Cpus
+			}
+			if u.Mems != "" {
+				c.Mems = u.Mems
+			}
+		}
+		if u := update.Memory; u != nil {
+			m := memory(s)
+			if u.Limit != nil {
+				m.Limit = u.Limit
+			}
+			if u.Swap != nil {
+				m.Swap = u.Swap
+			}
+		}
+		if update.Pids != nil {
+			r.Pids = update.Pids
+		}
+		if update.BlockIO != nil && update.BlockIO.Weight != nil {
+			if r.BlockIO == nil {
+				r.BlockIO = &specs.LinuxBlockIO{}
+			}
+			r.BlockIO.Weight = update.BlockIO.Weight
+		}
+		return nil
+	}
+}
+
+// WithAnnotations adds annotations, for example the image's stop signal
+func WithAnnotations(annotations map[string]string) SpecOpts {
+	return func(s *specs.Spec) error {
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			s.Annotations[k] = v
+		}
+		return nil
+	}
+}
+
+// WithImageConfig fills in what the spec leaves open from an image config: the
+// command when the spec has no args, the working directory when it has no cwd
+// and the user when it runs as root, with user and group names looked up in
+// rootfs, the image's unpacked root filesystem. The image's environment is kept for the
+// variables the spec does not set and its stop signal becomes an annotation.
+func WithImageConfig(config ocispec.ImageConfig, rootfs string) SpecOpts {
+	return func(s *specs.Spec) error {
+		if len(s.Process.Args) == 0 {
+			s.Process.Args = append(append([]string{}, config.Entrypoint...), config.Cmd...)
+		}
+		if len(s.Process.Args) == 0 {
+			return errors.New("neither the spec nor the image config give a command to run")
+		}
+		if s.Process.Cwd == "" {
+			s.Process.Cwd = config.WorkingDir
+		}
+		if s.Process.Cwd == "" {
+			s.Process.Cwd = "/"
+		}
+		if config.User != "" && s.Process.User.UID == 0 && s.Process.User.GID == 0 {
+			if err := WithRootfsUser(config.User, rootfs)(s); err != nil {
+				return fmt.Errorf("image user: %w", err)
+			}
+		}
+		env := s.Process.Env
+		s.Process.Env = append([]string{}, config.Env...)
+		if err := WithEnv(env)(s); err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(s.Process.Env, func(kv string) bool { return strings.HasPrefix(kv, "PATH=") }) {
+			s.Process.Env = append(s.Process.Env, defaultPath)
+		}
+		if config.StopSignal != "" {
+			if _, ok := s.Annotations[AnnotationStopSignal]; !ok {
+				return WithAnnotations(map[string]string{AnnotationStopSignal: config.StopSignal})(s)
+			}
+		}
+		return nil
+	}
+}
+
+func cpu(s *specs.Spec) *specs.LinuxCPU {
+	r := resources(s)
+	if r.CPU == nil {
+		r.CPU = &specs.LinuxCPU{}
+	}
+	return r.CPU
+}
+
+func memory(s *specs.Spec) *specs.LinuxMemory {
+	r := resources(s)
+	if r.Memory == nil {
+		r.Memory = &specs.LinuxMemory{}
+	}
+	return r.Memory
+}
+
+func resources(s *specs.Spec) *specs.LinuxResources {
+	if s.Linux == nil {
+		s.Linux = &specs.Linux{}
+	}
+	if s.Linux.Resources == nil {
+		s.Linux.Resources = &specs.LinuxResources{}
+	}
+	return s.Linux.Resources
+}
@@ -0,0 +1,337 @@
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func TestGenerateSpec(t *testing.T) {
+	spec, err := GenerateSpec(
+		WithProcessArgs("nginx", "-g", "daemon off;"),
+		WithTTY(true),
+		WithHostname("web"),
+		WithProcessCwd("/srv"),
+		WithRootfsReadonly(false),
+		WithAnnotations(map[string]string{"owner": "ops"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Process.Args) != 3 || spec.Process.Args[0] != "nginx" {
+		t.Errorf("args = %q", spec.Process.Args)
+	}
+	if !spec.Process.Terminal || spec.Hostname != "web" || spec.Process.Cwd != "/srv" || spec.Root.Readonly {
+		t.Errorf("process = %+v, hostname %q, root %+v", spec.Process, spec.Hostname, spec.Root)
+	}
+	if spec.Annotations["owner"] != "ops" {
+		t.Errorf("annotations = %v", spec.Annotations)
+	}
+	if err := Validate(spec); err != nil {
+		t.Errorf("generated spec does not validate: %v", err)
+	}
+
+	if _, err := GenerateSpec(WithMemoryLimit(0)); err == nil {
+		t.Error("GenerateSpec ignored a failing option")
+	}
+}
+
+func TestWithEnv(t *testing.T) {
+	spec := DefaultSpec()
+	spec.Process.Env = []string{"PATH=/bin", "TERM=xterm"}
+	if err := WithEnv([]string{"TERM=dumb", "LANG=C.UTF-8"})(spec); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PATH=/bin", "TERM=dumb", "LANG=C.UTF-8"}
+	if !slices.Equal(spec.Process.Env, want) {
+		t.Errorf("env = %q, want %q", spec.Process.Env, want)
+	}
+	for _, kv := range []string{"NOVALUE", "=value"} {
+		if err := WithEnv([]string{kv})(spec); err == nil {
+			t.Errorf("WithEnv accepted %q", kv)
+		}
+	}
+}
+
+func TestWithMounts(t *testing.T) {
+	spec := DefaultSpec()
+	count := len(spec.Mounts)
+	err := WithMounts([]specs.Mount{
+		{Destination: "/tmp", Type: "tmpfs", Source: "tmpfs"},
+		{Destination: "/data", Type: "bind", Source: "/srv/data", Options: []string{"rbind"}},
+	})(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Mounts) != count+2 {
+		t.Errorf("%d mounts, want %d", len(spec.Mounts), count+2)
+	}
+
+	// a mount of the default spec is replaced rather than mounted over
+	err = WithMounts([]specs.Mount{{Destination: "/dev/shm", Type: "tmpfs", Source: "shm", Options: []string{"size=1g"}}})(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shm := 0
+	for _, m := range spec.Mounts {
+		if m.Destination == "/dev/shm" {
+			shm++
+			if !slices.Contains(m.Options, "size=1g") {
+				t.Errorf("/dev/shm options = %q", m.Options)
+			}
+		}
+	}
+	if shm != 1 {
+		t.Errorf("%d mounts at /dev/shm, want 1", shm)
+	}
+
+	if err := WithMounts([]specs.Mount{{Source: "/srv"}})(spec); err == nil {
+		t.Error("mount without a destination accepted")
+	}
+}
+
+func TestWithUser(t *testing.T) {
+	tests := []struct {
+		user     string
+		uid, gid uint32
+	}{
+		{"1000", 1000, 1000},
+		{"1000:50", 1000, 50},
+		{"0:0", 0, 0},
+	}
+	for _, tt := range tests {
+		spec := DefaultSpec()
+		if err := WithUser(tt.user)(spec); err != nil {
+			t.Errorf("WithUser(%q): %v", tt.user, err)
+			continue
+		}
+		if spec.Process.User.UID != tt.uid || spec.Process.User.GID != tt.gid {
+			t.Errorf("WithUser(%q) = %d:%d, want %d:%d", tt.user, spec.Process.User.UID, spec.Process.User.GID, tt.uid, tt.gid)
+		}
+	}
+	for _, user := range []string{"node", "1000:staff", "-1", ""} {
+		if err := WithUser(user)(DefaultSpec()); err == nil {
+			t.Errorf("WithUser accepted %q", user)
+		}
+	}
+}
+
+// writeRootfs creates a root filesystem with the given /etc/passwd and
+// /etc/group
+func writeRootfs(t *testing.T, passwd, group string) string {
+	t.Helper()
+	rootfs := t.TempDir()
+	if err := os.Mkdir(filepath.Join(rootfs, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "passwd"), []byte(passwd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "group"), []byte(group), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return rootfs
+}
+
+func TestWithRootfsUser(t *testing.T) {
+	rootfs := writeRootfs(t,
+		"root:x:0:0:root:/root:/bin/sh\nnode:x:1000:1000::/home/node:/bin/sh\n",
+		"root:x:0:\nnode:x:1000:\nstaff:x:50:node\naudio:x:63:node,root\n")
+	tests := []struct {
+		user     string
+		uid, gid uint32
+		groups   []uint32
+	}{
+		{"node", 1000, 1000, []uint32{50, 63}},
+		{"node:staff", 1000, 50, nil},
+		{"node:63", 1000, 63, nil},
+		{"1000", 1000, 1000, []uint32{50, 63}},
+		// a uid without an entry runs in group 0
+		{"2000", 2000, 0, nil},
+		{"2000:staff", 2000, 50, nil},
+	}
+	for _, tt := range tests {
+		spec := DefaultSpec()
+		if err := WithRootfsUser(tt.user, rootfs)(spec); err != nil {
+			t.Errorf("WithRootfsUser(%q): %v", tt.user, err)
+			continue
+		}
+		got := spec.Process.User
+		if got.UID != tt.uid || got.GID != tt.gid || !slices.Equal(got.AdditionalGids, tt.groups) {
+			t.Errorf("WithRootfsUser(%q) = %d:%d %v, want %d:%d %v", tt.user, got.UID, got.GID, got.AdditionalGids, tt.uid, tt.gid, tt.groups)
+		}
+	}
+	for _, user := range []string{"nobody", "node:wheel"} {
+		if err := WithRootfsUser(user, rootfs)(DefaultSpec()); err == nil {
+			t.Errorf("WithRootfsUser accepted %q, which the rootfs does not know", user)
+		}
+	}
+}
+
+func TestWithRootfsUserStaysInRoot(t *testing.T) {
+	host := writeRootfs(t, "node:x:1000:1000::/home/node:/bin/sh\n", "node:x:1000:\n")
+	rootfs := t.TempDir()
+	if err := os.Mkdir(filepath.Join(rootfs, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// the image's files point at the host's, which must not be read
+	for _, name := range []string{"passwd", "group"} {
+		if err := os.Symlink(filepath.Join("../../../../../..", host, "etc", name), filepath.Join(rootfs, "etc", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := WithRootfsUser("node", rootfs)(DefaultSpec()); err == nil {
+		t.Error("user looked up in a passwd file outside of the rootfs")
+	}
+
+	// without the files only numeric IDs work
+	spec := DefaultSpec()
+	if err := WithRootfsUser("5:6", t.TempDir())(spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Process.User.UID != 5 || spec.Process.User.GID != 6 {
+		t.Errorf("user = %+v, want 5:6", spec.Process.User)
+	}
+}
+
+func TestResourceOpts(t *testing.T) {
+	spec, err := GenerateSpec(
+		WithCPUs(1.5),
+		WithCPUShares(512),
+		WithCPUSet("0-1", ""),
+		WithMemoryLimit(256<<20),
+		WithMemorySwap(-1),
+		WithPidsLimit(100),
+		WithIOWeight(500),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := spec.Linux.Resources
+	if *r.CPU.Quota != 150000 || *r.CPU.Period != cpuPeriod || *r.CPU.Shares != 512 || r.CPU.Cpus != "0-1" || r.CPU.Mems != "" {
+		t.Errorf("cpu = %+v", r.CPU)
+	}
+	if *r.Memory.Limit != 256<<20 || *r.Memory.Swap != -1 {
+		t.Errorf("memory = %+v", r.Memory)
+	}
+	if r.Pids.Limit != 100 || *r.BlockIO.Weight != 500 {
+		t.Errorf("pids = %+v, blkio = %+v", r.Pids, r.BlockIO)
+	}
+	if err := ValidateResources(r); err != nil {
+		t.Errorf("ValidateResources: %v", err)
+	}
+
+	invalid := map[string]SpecOpts{
+		"cpus":        WithCPUs(0),
+		"cpu shares":  WithCPUShares(1),
+		"memory":      WithMemoryLimit(-5),
+		"memory swap": WithMemorySwap(0),
+		"pids":        WithPidsLimit(0),
+		"io weight":   WithIOWeight(5000),
+	}
+	for name, opt := range invalid {
+		if err := opt(DefaultSpec()); err == nil {
+			t.Errorf("invalid %s accepted", name)
+		}
+	}
+}
+
+func TestWithResourceUpdates(t *testing.T) {
+	spec, err := GenerateSpec(WithCPUs(2), WithMemoryLimit(512<<20), WithPidsLimit(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices := spec.Linux.Resources.Devices
+	limit := int64(1 << 30)
+	if err := WithResourceUpdates(&specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}})(spec); err != nil {
+		t.Fatal(err)
+	}
+	r := spec.Linux.Resources
+	if *r.Memory.Limit != 1<<30 {
+		t.Errorf("memory limit = %d, want the update", *r.Memory.Limit)
+	}
+	if *r.CPU.Quota != 200000 || r.Pids.Limit != 50 {
+		t.Errorf("limits the update leaves out changed: cpu %+v, pids %+v", r.CPU, r.Pids)
+	}
+	if len(r.Devices) != len(devices) {
+		t.Errorf("device rules changed")
+	}
+
+	// WithResources replaces everything but the device rules
+	if err := WithResources(&specs.LinuxResources{Pids: &specs.LinuxPids{Limit: 10}})(spec); err != nil {
+		t.Fatal(err)
+	}
+	if r := spec.Linux.Resources; r.CPU != nil || r.Memory != nil || r.Pids.Limit != 10 || len(r.Devices) != len(devices) {
+		t.Errorf("resources = %+v", r)
+	}
+}
+
+func TestWithImageConfig(t *testing.T) {
+	rootfs := writeRootfs(t, "root:x:0:0::/root:/bin/sh\nnginx:x:101:101::/:/sbin/nologin\n", "nginx:x:101:\n")
+	config := ocispec.ImageConfig{
+		User:       "nginx",
+		Entrypoint: []string{"/docker-entrypoint.sh"},
+		Cmd:        []string{"nginx", "-g", "daemon off;"},
+		Env:        []string{"PATH=/usr/sbin:/usr/bin", "NGINX_VERSION=1.27"},
+		WorkingDir: "/usr/share/nginx",
+		StopSignal: "SIGQUIT",
+	}
+
+	// what the spec leaves open comes from the image
+	spec := DefaultSpec()
+	spec.Process.Args = nil
+	spec.Process.Cwd = ""
+	spec.Process.Env = []string{"NGINX_VERSION=1.26"}
+	if err := WithImageConfig(config, rootfs)(spec); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/docker-entrypoint.sh", "nginx", "-g", "daemon off;"}; !slices.Equal(spec.Process.Args, want) {
+		t.Errorf("args = %q, want %q", spec.Process.Args, want)
+	}
+	if spec.Process.Cwd != "/usr/share/nginx" {
+		t.Errorf("cwd = %q", spec.Process.Cwd)
+	}
+	if spec.Process.User.UID != 101 || spec.Process.User.GID != 101 {
+		t.Errorf("user = %+v, want nginx", spec.Process.User)
+	}
+	if want := []string{"PATH=/usr/sbin:/usr/bin", "NGINX_VERSION=1.26"}; !slices.Equal(spec.Process.Env, want) {
+		t.Errorf("env = %q, want %q", spec.Process.Env, want)
+	}
+	if spec.Annotations[AnnotationStopSignal] != "SIGQUIT" {
+		t.Errorf("annotations = %v", spec.Annotations)
+	}
+
+	// what the spec sets is kept
+	spec = DefaultSpec()
+	spec.Process.User = specs.User{UID: 5, GID: 5}
+	spec.Annotations = map[string]string{AnnotationStopSignal: "SIGTERM"}
+	if err := WithImageConfig(config, rootfs)(spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Process.Args[0] != "sh" || spec.Process.Cwd != "/" || spec.Process.User.UID != 5 {
+		t.Errorf("spec overridden by the image: %+v", spec.Process)
+	}
+	if spec.Annotations[AnnotationStopSignal] != "SIGTERM" {
+		t.Errorf("stop signal overridden by the image")
+	}
+
+	// an image without a command, user, cwd or PATH
+	spec = DefaultSpec()
+	if err := WithImageConfig(ocispec.ImageConfig{}, "")(spec); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(spec.Process.Env, defaultPath) || spec.Process.Cwd != "/" {
+		t.Errorf("process = %+v, want the default PATH and /", spec.Process)
+	}
+	spec.Process.Args = nil
+	if err := WithImageConfig(ocispec.ImageConfig{}, "")(spec); err == nil {
+		t.Error("spec without a command accepted")
+	}
+	if err := WithImageConfig(ocispec.ImageConfig{User: "nobody"}, rootfs)(DefaultSpec()); err == nil {
+		t.Error("unknown image user accepted")
+	}
+}
@@ -0,0 +1,151 @@
+// Package oci builds and checks the OCI runtime specs containers are created
+// from.
+//
+// GenerateSpec starts from the spec runc spec generates and applies SpecOpts
+// on top of it:
+//
+//	spec, err := oci.GenerateSpec(oci.WithHostname(id), oci.WithProcessArgs("sleep", "60"))
+//
+// Specs and resource updates travel in a google.protobuf.Any under the type
+// URLs containerd uses, which importing this package registers with typeurl.
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func init() {
+	typeurl.Register(&specs.Spec{}, "types.containerd.io", "opencontainers/runtime-spec", strconv.Itoa(specs.VersionMajor), "Spec")
+	typeurl.Register(&specs.LinuxResources{}, "types.containerd.io", "opencontainers/runtime-spec", strconv.Itoa(specs.VersionMajor), "LinuxResources")
+}
+
+// SpecOpts changes a spec, failing if it cannot be applied
+type SpecOpts func(*specs.Spec) error
+
+// GenerateSpec returns the default spec with opts applied in order
+func GenerateSpec(opts ...SpecOpts) (*specs.Spec, error) {
+	spec := DefaultSpec()
+	for _, opt := range opts {
+		if err := opt(spec); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+// DefaultSpec is the spec runc spec generates: sh on a read-only rootfs
+// directory in the bundle with the usual namespaces, mounts and capabilities
+func DefaultSpec() *specs.Spec {
+	caps := []string{"CAP_AUDIT_WRITE", "CAP_KILL", "CAP_NET_BIND_SERVICE"}
+	return &specs.Spec{
+		Version: specs.Version,
+		Root: &specs.Root{
+			Path:     "rootfs",
+			Readonly: true,
+		},
+		Process: &specs.Process{
+			Args: []string{"sh"},
+			Env:  []string{defaultPath, "TERM=xterm"},
+			Cwd:  "/",
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding:  caps,
+				Permitted: caps,
+				Effective: caps,
+			},
+			Rlimits: []specs.POSIXRlimit{
+				{Type: "RLIMIT_NOFILE", Hard: 1024, Soft: 1024},
+			},
+			NoNewPrivileges: true,
+		},
+		Hostname: "runc",
+		Mounts: []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc"},
+			{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: []string{"nosuid", "strictatime", "mode=755", "size=65536k"}},
+			{Destination: "/dev/pts", Type: "devpts", Source: "devpts", Options: []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"}},
+			{Destination: "/dev/shm", Type: "tmpfs", Source: "shm", Options: []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"}},
+			{Destination: "/dev/mqueue", Type: "mqueue", Source: "mqueue", Options: []string{"nosuid", "noexec", "nodev"}},
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "noexec", "nodev", "ro"}},
+			{Destination: "/sys/fs/cgroup", Type: "cgroup", Source: "cgroup", Options: []string{"nosuid", "noexec", "nodev", "relatime", "ro"}},
+		},
+		Linux: &specs.Linux{
+			Resources: &specs.LinuxResources{
+				Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+			},
+			Namespaces: []specs.LinuxNamespace{
+				{Type: specs.PIDNamespace},
+				{Type: specs.NetworkNamespace},
+				{Type: specs.IPCNamespace},
+				{Type: specs.UTSNamespace},
+				{Type: specs.MountNamespace},
+				{Type: specs.CgroupNamespace},
+			},
+			MaskedPaths: []string{
+				"/proc/acpi", "/proc/asound", "/proc/kcore", "/proc/keys", "/proc/latency_stats",
+				"/proc/timer_list", "/proc/timer_stats", "/proc/sched_debug", "/sys/firmware",
+				"/proc/scsi",
+			},
+			ReadonlyPaths: []string{
+				"/proc/bus", "/proc/fs", "/proc/irq", "/proc/sys", "/proc/sysrq-trigger",
+			},
+		},
+	}
+}
+
+// Validate checks that spec has what runc needs to create a container from it
+func Validate(spec *specs.Spec) error {
+	switch {
+	case spec == nil:
+		return errors.New("spec is empty")
+	case spec.Version == "":
+		return errors.New("spec has no version")
+	case spec.Root == nil || spec.Root.Path == "":
+		return errors.New("spec has no root path")
+	case spec.Process == nil:
+		return errors.New("spec has no process")
+	case len(spec.Process.Args) == 0:
+		return errors.New("spec process has no args")
+	case spec.Process.Cwd == "" || spec.Process.Cwd[0] != '/':
+		return fmt.Errorf("spec process cwd %q is not absolute", spec.Process.Cwd)
+	case spec.Linux == nil:
+		return errors.New("spec has no linux section")
+	}
+	for _, m := range spec.Mounts {
+		if m.Destination == "" {
+			return fmt.Errorf("mount of %q has no destination", m.Source)
+		}
+	}
+	return ValidateResources(spec.Linux.Resources)
+}
+
+// ValidateResources checks the cgroup limits for values runc would refuse
+func ValidateResources(r *specs.LinuxResources) error {
+	if r == nil {
+		return nil
+	}
+	if c := r.CPU; c != nil {
+		if c.Shares != nil && (*c.Shares < 2 || *c.Shares > 262144) {
+			return fmt.Errorf("cpu shares %d out of range 2 to 262144", *c.Shares)
+		}
+		if c.Quota != nil && *c.Quota <= 0 && *c.Quota != -1 {
+			return fmt.Errorf("invalid cpu quota %d", *c.Quota)
+		}
+		if c.Period != nil && *c.Period < 1000 {
+			return fmt.Errorf("cpu period %d is below 1000", *c.Period)
+		}
+	}
+	if m := r.Memory; m != nil && m.Limit != nil && m.Swap != nil {
+		// swap counts memory and swap together
+		if *m.Swap != -1 && *m.Limit > 0 && *m.Swap < *m.Limit {
+			return fmt.Errorf("memory and swap limit %d is below the memory limit %d", *m.Swap, *m.Limit)
+		}
+	}
+	if b := r.BlockIO; b != nil && b.Weight != nil && (*b.Weight < 10 || *b.Weight > 1000) {
+		return fmt.Errorf("io weight %d out of range 10 to 1000", *b.Weight)
+	}
+	return nil
+}
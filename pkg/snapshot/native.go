@@ -0,0 +1,43 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/continuity/fs"
+)
+
+// native gives every snapshot a full copy of its parent. It takes up far more
+// space than overlay but works on any filesystem.
+type native struct{}
+
+// NewNative opens the native snapshotter under root
+func NewNative(root string) (Snapshotter, error) {
+	return newSnapshotter(root, native{})
+}
+
+func (native) create(s *snapshot, dir string, parents []string) error {
+	target := filepath.Join(dir, "fs")
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	if len(parents) == 0 {
+		return nil
+	}
+	// the parent already holds the contents of all its own ancestors
+	return fs.CopyDir(target, filepath.Join(parents[0], "fs"))
+}
+
+func (native) mounts(s *snapshot, dir string, parents []string) []mount.Mount {
+	mode := "rw"
+	if s.Kind == snapshots.KindView {
+		mode = "ro"
+	}
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  filepath.Join(dir, "fs"),
+		Options: []string{mode, "rbind"},
+	}}
+}
@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/containerd/v2/plugins/snapshots/overlay/overlayutils"
+)
+
+// overlay keeps every snapshot in an fs dir used as an overlay upper dir
+// while the snapshot is active and as a lower dir once it is committed
+type overlay struct{}
+
+// NewOverlay opens the overlayfs snapshotter under root, failing if the
+// filesystem root is on cannot hold overlay mounts
+func NewOverlay(root string) (Snapshotter, error) {
+	if err := overlayutils.Supported(root); err != nil {
+		return nil, fmt.Errorf("overlayfs is not supported on %s: %w", root, err)
+	}
+	return newSnapshotter(root, overlay{})
+}
+
+func (overlay) create(s *snapshot, dir string, parents []string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "fs"), 0755); err != nil {
+		return err
+	}
+	if s.Kind == snapshots.KindActive {
+		// overlay needs an empty work dir on the same filesystem as the upper dir
+		return os.Mkdir(filepath.Join(dir, "work"), 0711)
+	}
+	return nil
+}
+
+func (overlay) mounts(s *snapshot, dir string, parents []string) []mount.Mount {
+	// overlay needs at least one lower dir, without parents the snapshot's
+	// own dir is bind mounted instead
+	if len(parents) == 0 {
+		mode := "rw"
+		if s.Kind == snapshots.KindView {
+			mode = "ro"
+		}
+		return []mount.Mount{{
+			Type:    "bind",
+			Source:  filepath.Join(dir, "fs"),
+			Options: []string{mode, "rbind"},
+		}}
+	}
+
+	var options []string
+	if s.Kind == snapshots.KindActive {
+		options = append(options,
+			"workdir="+filepath.Join(dir, "work"),
+			"upperdir="+filepath.Join(dir, "fs"),
+		)
+	} else if len(parents) == 1 {
+		// a view of a single layer is that layer
+		return []mount.Mount{{
+			Type:    "bind",
+			Source:  filepath.Join(parents[0], "fs"),
+			Options: []string{"ro", "rbind"},
+		}}
+	}
+	lower := make([]string, len(parents))
+	for i, p := range parents {
+		lower[i] = filepath.Join(p, "fs")
+	}
+	options = append(options, "lowerdir="+strings.Join(lower, ":"))
+	return []mount.Mount{{
+		Type:    "overlay",
+		Source:  "overlay",
+		Options: options,
+	}}
+}
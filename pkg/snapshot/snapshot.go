@@ -0,0 +1,357 @@
+// Package snapshot keeps the root filesystems of containers as stacks of
+// snapshots, so containers created from the same image share its layers on
+// disk instead of each holding a copy.
+//
+// Snapshots are either committed, read-only and usable as the parent of other
+// snapshots, or active, writable until committed. Views are read-only
+// snapshots that can never be committed. An image is unpacked by preparing an
+// active snapshot on top of the previous layer, applying the layer to it and
+// committing the result; a container's rootfs is an active snapshot on top of
+// the image's last layer.
+//
+// The overlayfs backend stacks the directories of committed snapshots as
+// overlay lower dirs under the writable upper dir of the active one. The
+// native backend copies the parent into every new snapshot instead, for
+// filesystems overlayfs cannot be used on.
+//
+// Snapshot metadata is kept in <root>/metadata.json and the contents of a
+// snapshot under <root>/snapshots/<id>.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/continuity/fs"
+	"github.com/containerd/errdefs"
+)
+
+// Snapshotter manages snapshots by key, the way containerd's
+// snapshots.Snapshotter does. Committed snapshots are known by the name they
+// were committed under.
+type Snapshotter interface {
+	// Stat returns the info of the snapshot key
+	Stat(ctx context.Context, key string) (snapshots.Info, error)
+	// Usage returns the disk space the snapshot key takes up itself, not
+	// counting its parents
+	Usage(ctx context.Context, key string) (snapshots.Usage, error)
+	// Mounts returns the mounts of an active snapshot or view
+	Mounts(ctx context.Context, key string) ([]mount.Mount, error)
+	// Prepare creates an active snapshot on top of the committed snapshot
+	// parent, or an empty one without a parent
+	Prepare(ctx context.Context, key, parent string) ([]mount.Mount, error)
+	// View creates a read-only snapshot of the committed snapshot parent
+	View(ctx context.Context, key, parent string) ([]mount.Mount, error)
+	// Commit turns the active snapshot key into the committed snapshot name
+	Commit(ctx context.Context, name, key string) error
+	// Remove removes a snapshot no other snapshot is based on
+	Remove(ctx context.Context, key string) error
+}
+
+// driver lays out and mounts the contents of snapshots for a backend
+type driver interface {
+	// create sets up the contents of the new snapshot s in dir. parents are
+	// the directories of its ancestors, the nearest first.
+	create(s *snapshot, dir string, parents []string) error
+	// mounts returns the mounts of the snapshot s kept in dir
+	mounts(s *snapshot, dir string, parents []string) []mount.Mount
+}
+
+// snapshot is the metadata recorded for a snapshot
+type snapshot struct {
+	ID      string         `json:"id"`
+	Kind    snapshots.Kind `json:"kind"`
+	Parent  string         `json:"parent,omitempty"`
+	Created time.Time      `json:"created"`
+	Updated time.Time      `json:"updated"`
+	// Usage of a committed snapshot, which no longer changes, is measured
+	// once when it is committed
+	Usage snapshots.Usage `json:"usage"`
+}
+
+// snapshotter implements Snapshotter on top of a driver
+type snapshotter struct {
+	// mu guards the snapshots and the metadata file
+	mu        sync.Mutex
+	root      string
+	driver    driver
+	nextID    uint64
+	snapshots map[string]*snapshot
+}
+
+// metadataFile is the layout of metadata.json
+type metadataFile struct {
+	NextID    uint64               `json:"next_id"`
+	Snapshots map[string]*snapshot `json:"snapshots"`
+}
+
+func newSnapshotter(root string, d driver) (*snapshotter, error) {
+	if err := os.MkdirAll(filepath.Join(root, "snapshots"), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	s := &snapshotter{
+		root:      root,
+		driver:    d,
+		nextID:    1,
+		snapshots: map[string]*snapshot{},
+	}
+	data, err := os.ReadFile(s.metadataPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read snapshot metadata: %w", err)
+	}
+	if len(data) > 0 {
+		var md metadataFile
+		if err := json.Unmarshal(data, &md); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot metadata: %w", err)
+		}
+		s.nextID = md.NextID
+		if md.Snapshots != nil {
+			s.snapshots = md.Snapshots
+		}
+	}
+	return s, nil
+}
+
+func (s *snapshotter) Stat(ctx context.Context, key string) (snapshots.Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sn, err := s.get(key)
+	if err != nil {
+		return snapshots.Info{}, err
+	}
+	return snapshots.Info{
+		Kind:    sn.Kind,
+		Name:    key,
+		Parent:  sn.Parent,
+		Created: sn.Created,
+		Updated: sn.Updated,
+	}, nil
+}
+
+func (s *snapshotter) Usage(ctx context.Context, key string) (snapshots.Usage, error) {
+	s.mu.Lock()
+	sn, err := s.get(key)
+	if err != nil {
+		s.mu.Unlock()
+		return snapshots.Usage{}, err
+	}
+	if sn.Kind == snapshots.KindCommitted {
+		s.mu.Unlock()
+		return sn.Usage, nil
+	}
+	dir := s.dir(sn.ID)
+	s.mu.Unlock()
+
+	// an active snapshot keeps changing, measure it now
+	du, err := fs.DiskUsage(ctx, filepath.Join(dir, "fs"))
+	if err != nil {
+		return snapshots.Usage{}, fmt.Errorf("failed to measure snapshot %s: %w", key, err)
+	}
+	return snapshots.Usage{Inodes: du.Inodes, Size: du.Size}, nil
+}
+
+func (s *snapshotter) Mounts(ctx context.Context, key string) ([]mount.Mount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sn, err := s.get(key)
+	if err != nil {
+		return nil, err
+	}
+	if sn.Kind == snapshots.KindCommitted {
+		return nil, fmt.Errorf("snapshot %s is committed, it cannot be mounted: %w", key, errdefs.ErrFailedPrecondition)
+	}
+	return s.driver.mounts(sn, s.dir(sn.ID), s.parentDirs(sn)), nil
+}
+
+func (s *snapshotter) Prepare(ctx context.Context, key, parent string) ([]mount.Mount, error) {
+	return s.create(key, parent, snapshots.KindActive)
+}
+
+func (s *snapshotter) View(ctx context.Context, key, parent string) ([]mount.Mount, error) {
+	return s.create(key, parent, snapshots.KindView)
+}
+
+func (s *snapshotter) create(key, parent string, kind snapshots.Kind) ([]mount.Mount, error) {
+	if key == "" {
+		return nil, fmt.Errorf("snapshot key is required: %w", errdefs.ErrInvalidArgument)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.snapshots[key]; ok {
+		return nil, fmt.Errorf("snapshot %s: %w", key, errdefs.ErrAlreadyExists)
+	}
+	if parent != "" {
+		p, err := s.get(parent)
+		if err != nil {
+			return nil, fmt.Errorf("parent of %s: %w", key, err)
+		}
+		if p.Kind != snapshots.KindCommitted {
+			return nil, fmt.Errorf("parent %s of %s is not committed: %w", parent, key, errdefs.ErrInvalidArgument)
+		}
+	}
+
+	now := time.Now().UTC()
+	sn := &snapshot{
+		ID:      strconv.FormatUint(s.nextID, 10),
+		Kind:    kind,
+		Parent:  parent,
+		Created: now,
+		Updated: now,
+	}
+	dir := s.dir(sn.ID)
+	parents := s.parentDirs(sn)
+	// a directory left over from a snapshot that was never recorded is stale
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot %s: %w", key, err)
+	}
+	if err := s.driver.create(sn, dir, parents); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to create snapshot %s: %w", key, err)
+	}
+	s.snapshots[key] = sn
+	s.nextID++
+	if err := s.writeMetadata(); err != nil {
+		delete(s.snapshots, key)
+		s.nextID--
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return s.driver.mounts(sn, dir, parents), nil
+}
+
+func (s *snapshotter) Commit(ctx context.Context, name, key string) error {
+	if name == "" {
+		return fmt.Errorf("snapshot name is required: %w", errdefs.ErrInvalidArgument)
+	}
+	s.mu.Lock()
+	sn, err := s.get(key)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	if sn.Kind != snapshots.KindActive {
+		s.mu.Unlock()
+		return fmt.Errorf("snapshot %s is not active: %w", key, errdefs.ErrFailedPrecondition)
+	}
+	dir := s.dir(sn.ID)
+	s.mu.Unlock()
+
+	// measured without holding the lock, the caller is done writing to key
+	du, err := fs.DiskUsage(context.Background(), filepath.Join(dir, "fs"))
+	if err != nil {
+		return fmt.Errorf("failed to measure snapshot %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshots[key] != sn {
+		return fmt.Errorf("snapshot %s was removed while being committed: %w", key, errdefs.ErrNotFound)
+	}
+	if _, ok := s.snapshots[name]; ok {
+		return fmt.Errorf("snapshot %s: %w", name, errdefs.ErrAlreadyExists)
+	}
+	committed := *sn
+	committed.Kind = snapshots.KindCommitted
+	committed.Updated = time.Now().UTC()
+	committed.Usage = snapshots.Usage{Inodes: du.Inodes, Size: du.Size}
+	delete(s.snapshots, key)
+	s.snapshots[name] = &committed
+	if err := s.writeMetadata(); err != nil {
+		delete(s.snapshots, name)
+		s.snapshots[key] = sn
+		return err
+	}
+	return nil
+}
+
+func (s *snapshotter) Remove(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sn, err := s.get(key)
+	if err != nil {
+		return err
+	}
+	for child, c := range s.snapshots {
+		if c.Parent == key {
+			return fmt.Errorf("snapshot %s is the parent of %s: %w", key, child, errdefs.ErrFailedPrecondition)
+		}
+	}
+	delete(s.snapshots, key)
+	if err := s.writeMetadata(); err != nil {
+		s.snapshots[key] = sn
+		return err
+	}
+	if err := os.RemoveAll(s.dir(sn.ID)); err != nil {
+		return fmt.Errorf("failed to remove snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+// get looks up key, the caller holds s.mu
+func (s *snapshotter) get(key string) (*snapshot, error) {
+	sn, ok := s.snapshots[key]
+	if !ok {
+		return nil, fmt.Errorf("snapshot %s: %w", key, errdefs.ErrNotFound)
+	}
+	return sn, nil
+}
+
+// parentDirs returns the directories of the ancestors of sn, the nearest
+// first. The caller holds s.mu.
+func (s *snapshotter) parentDirs(sn *snapshot) []string {
+	var dirs []string
+	for parent := sn.Parent; parent != ""; {
+		p, ok := s.snapshots[parent]
+		if !ok {
+			break
+		}
+		dirs = append(dirs, s.dir(p.ID))
+		parent = p.Parent
+	}
+	return dirs
+}
+
+func (s *snapshotter) dir(id string) string {
+	return filepath.Join(s.root, "snapshots", id)
+}
+
+func (s *snapshotter) metadataPath() string {
+	return filepath.Join(s.root, "metadata.json")
+}
+
+// writeMetadata replaces metadata.json by renaming a fully written file over
+// it, the caller holds s.mu
+func (s *snapshotter) writeMetadata() error {
+	data, err := json.MarshalIndent(metadataFile{NextID: s.nextID, Snapshots: s.snapshots}, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.root, ".metadata-*")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.metadataPath()); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	return nil
+}
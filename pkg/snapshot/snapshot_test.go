@@ -0,0 +1,410 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/containerd/v2/plugins/snapshots/overlay/overlayutils"
+	"github.com/containerd/errdefs"
+)
+
+var backends = []struct {
+	name string
+	open func(root string) (Snapshotter, error)
+}{
+	{"native", NewNative},
+	{"overlay", NewOverlay},
+}
+
+// openBackend opens the backend under a new directory, skipping the test when
+// overlayfs cannot be used there
+func openBackend(t *testing.T, name string, open func(string) (Snapshotter, error)) Snapshotter {
+	t.Helper()
+	root := t.TempDir()
+	if name == "overlay" {
+		if err := overlayutils.Supported(root); err != nil {
+			t.Skipf("overlayfs is not supported: %v", err)
+		}
+	}
+	sn, err := open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sn
+}
+
+// writeFile writes data to name in the snapshot the bind mount mounts refers to
+func writeFile(t *testing.T, mounts []mount.Mount, name, data string) {
+	t.Helper()
+	if len(mounts) != 1 || mounts[0].Type != "bind" {
+		t.Fatalf("mounts = %+v, want a single bind mount", mounts)
+	}
+	if err := os.WriteFile(filepath.Join(mounts[0].Source, name), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// prepareCommitted commits a snapshot holding the file name under key
+func prepareCommitted(t *testing.T, sn Snapshotter, key, name string) {
+	t.Helper()
+	ctx := context.Background()
+	mounts, err := sn.Prepare(ctx, key+"-active", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, mounts, name, "data")
+	if err := sn.Commit(ctx, key, key+"-active"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			ctx := context.Background()
+			sn := openBackend(t, b.name, b.open)
+
+			mounts, err := sn.Prepare(ctx, "base-active", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, mounts, "file", strings.Repeat("x", 8192))
+			active, err := sn.Usage(ctx, "base-active")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if active.Size < 8192 {
+				t.Errorf("usage of the active snapshot = %+v, want at least 8192 bytes", active)
+			}
+
+			if err := sn.Commit(ctx, "base", "base-active"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := sn.Stat(ctx, "base-active"); !errdefs.IsNotFound(err) {
+				t.Errorf("Stat of the committed key = %v, want not found", err)
+			}
+			info, err := sn.Stat(ctx, "base")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Kind != snapshots.KindCommitted || info.Name != "base" || info.Parent != "" {
+				t.Errorf("info = %+v", info)
+			}
+			committed, err := sn.Usage(ctx, "base")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if committed != active {
+				t.Errorf("usage of the committed snapshot = %+v, want %+v", committed, active)
+			}
+			if _, err := sn.Mounts(ctx, "base"); !errdefs.IsFailedPrecondition(err) {
+				t.Errorf("Mounts of a committed snapshot = %v, want a failed precondition", err)
+			}
+
+			mounts, err = sn.Prepare(ctx, "child", "base")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info, _ := sn.Stat(ctx, "child"); info.Kind != snapshots.KindActive || info.Parent != "base" {
+				t.Errorf("info = %+v", info)
+			}
+			if again, err := sn.Mounts(ctx, "child"); err != nil || len(again) != len(mounts) {
+				t.Errorf("Mounts = %+v, %v, want %+v", again, err, mounts)
+			}
+			if _, err := sn.View(ctx, "view", "base"); err != nil {
+				t.Fatal(err)
+			}
+
+			// base has children now
+			if err := sn.Remove(ctx, "base"); !errdefs.IsFailedPrecondition(err) {
+				t.Errorf("Remove of a parent = %v, want a failed precondition", err)
+			}
+			for _, key := range []string{"child", "view", "base"} {
+				if err := sn.Remove(ctx, key); err != nil {
+					t.Errorf("Remove %s: %v", key, err)
+				}
+				if _, err := sn.Stat(ctx, key); !errdefs.IsNotFound(err) {
+					t.Errorf("Stat of removed %s = %v, want not found", key, err)
+				}
+			}
+			if err := sn.Remove(ctx, "base"); !errdefs.IsNotFound(err) {
+				t.Errorf("second Remove = %v, want not found", err)
+			}
+		})
+	}
+}
+
+func TestNativeMounts(t *testing.T) {
+	ctx := context.Background()
+	sn := openBackend(t, "native", NewNative)
+	prepareCommitted(t, sn, "base", "file")
+
+	tests := []struct {
+		key  string
+		view bool
+		mode string
+	}{
+		{"active", false, "rw"},
+		{"view", true, "ro"},
+	}
+	for _, tt := range tests {
+		create := sn.Prepare
+		if tt.view {
+			create = sn.View
+		}
+		mounts, err := create(ctx, tt.key, "base")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mounts) != 1 || mounts[0].Type != "bind" || mounts[0].Options[0] != tt.mode {
+			t.Errorf("%s: mounts = %+v, want a %s bind mount", tt.key, mounts, tt.mode)
+			continue
+		}
+		// every snapshot holds a copy of its parent
+		if _, err := os.Stat(filepath.Join(mounts[0].Source, "file")); err != nil {
+			t.Errorf("%s: parent contents not copied: %v", tt.key, err)
+		}
+	}
+}
+
+func TestOverlayMounts(t *testing.T) {
+	ctx := context.Background()
+	sn := openBackend(t, "overlay", NewOverlay)
+	prepareCommitted(t, sn, "layer1", "file1")
+	if _, err := sn.Prepare(ctx, "layer2-active", "layer1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sn.Commit(ctx, "layer2", "layer2-active"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key, parent string
+		view        bool
+		typ         string
+		options     []string
+	}{
+		{"active", "layer2", false, "overlay", []string{"workdir=", "upperdir=", "lowerdir="}},
+		{"view-one", "layer1", true, "bind", []string{"ro", "rbind"}},
+		{"view-two", "layer2", true, "overlay", []string{"lowerdir="}},
+	}
+	for _, tt := range tests {
+		create := sn.Prepare
+		if tt.view {
+			create = sn.View
+		}
+		mounts, err := create(ctx, tt.key, tt.parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mounts) != 1 || mounts[0].Type != tt.typ || len(mounts[0].Options) != len(tt.options) {
+			t.Errorf("%s: mounts = %+v, want a %s mount", tt.key, mounts, tt.typ)
+			continue
+		}
+		for i, prefix := range tt.options {
+			if !strings.HasPrefix(mounts[0].Options[i], prefix) {
+				t.Errorf("%s: option %d = %q, want %s...", tt.key, i, mounts[0].Options[i], prefix)
+			}
+		}
+	}
+}
+
+func TestCreateErrors(t *testing.T) {
+	ctx := context.Background()
+	sn := openBackend(t, "native", NewNative)
+	prepareCommitted(t, sn, "committed", "file")
+	if _, err := sn.Prepare(ctx, "active", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		key, parent string
+		check       func(error) bool
+	}{
+		{"no key", "", "", errdefs.IsInvalidArgument},
+		{"key taken", "active", "", errdefs.IsAlreadyExists},
+		{"key taken by a committed snapshot", "committed", "", errdefs.IsAlreadyExists},
+		{"missing parent", "new", "missing", errdefs.IsNotFound},
+		{"active parent", "new", "active", errdefs.IsInvalidArgument},
+	}
+	for _, tt := range tests {
+		if _, err := sn.Prepare(ctx, tt.key, tt.parent); !tt.check(err) {
+			t.Errorf("%s: Prepare = %v", tt.name, err)
+		}
+		if _, err := sn.View(ctx, tt.key, tt.parent); !tt.check(err) {
+			t.Errorf("%s: View = %v", tt.name, err)
+		}
+	}
+}
+
+func TestCommitErrors(t *testing.T) {
+	ctx := context.Background()
+	sn := openBackend(t, "native", NewNative)
+	prepareCommitted(t, sn, "committed", "file")
+	if _, err := sn.Prepare(ctx, "active", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sn.View(ctx, "view", "committed"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, key string
+		check     func(error) bool
+	}{
+		{"", "active", errdefs.IsInvalidArgument},
+		{"new", "missing", errdefs.IsNotFound},
+		{"new", "view", errdefs.IsFailedPrecondition},
+		{"new", "committed", errdefs.IsFailedPrecondition},
+		{"committed", "active", errdefs.IsAlreadyExists},
+	}
+	for _, tt := range tests {
+		if err := sn.Commit(ctx, tt.name, tt.key); !tt.check(err) {
+			t.Errorf("Commit(%q, %q) = %v", tt.name, tt.key, err)
+		}
+	}
+	// a failed commit leaves the snapshot as it was
+	if info, err := sn.Stat(ctx, "active"); err != nil || info.Kind != snapshots.KindActive {
+		t.Errorf("active snapshot after failed commits: %+v, %v", info, err)
+	}
+}
+
+func TestCommitRemoveRace(t *testing.T) {
+	ctx := context.Background()
+	sn := openBackend(t, "native", NewNative)
+	for i := 0; i < 20; i++ {
+		mounts, err := sn.Prepare(ctx, "active", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// enough files for the usage to take a while to measure
+		for j := 0; j < 50; j++ {
+			writeFile(t, mounts, fmt.Sprintf("file%d", j), "data")
+		}
+
+		var commitErr, removeErr error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			commitErr = sn.Commit(ctx, "committed", "active")
+		}()
+		go func() {
+			defer wg.Done()
+			removeErr = sn.Remove(ctx, "active")
+		}()
+		wg.Wait()
+
+		// exactly one of them wins, a commit never records a removed snapshot
+		if (commitErr == nil) == (removeErr == nil) {
+			t.Fatalf("Commit = %v, Remove = %v, want exactly one to succeed", commitErr, removeErr)
+		}
+		if commitErr != nil {
+			if !errdefs.IsNotFound(commitErr) {
+				t.Fatalf("Commit = %v, want not found", commitErr)
+			}
+			if _, err := sn.Stat(ctx, "committed"); !errdefs.IsNotFound(err) {
+				t.Fatalf("Stat of the committed name after a lost commit = %v", err)
+			}
+			continue
+		}
+		if !errdefs.IsNotFound(removeErr) {
+			t.Fatalf("Remove = %v, want not found", removeErr)
+		}
+		if _, err := sn.View(ctx, "view", "committed"); err != nil {
+			t.Fatal(err)
+		}
+		if err := sn.Remove(ctx, "view"); err != nil {
+			t.Fatal(err)
+		}
+		if err := sn.Remove(ctx, "committed"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReloadMetadata(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	sn, err := NewNative(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prepareCommitted(t, sn, "base", "file")
+	if _, err := sn.Prepare(ctx, "child", "base"); err != nil {
+		t.Fatal(err)
+	}
+	usage, err := sn.Usage(ctx, "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewNative(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key    string
+		kind   snapshots.Kind
+		parent string
+	}{
+		{"base", snapshots.KindCommitted, ""},
+		{"child", snapshots.KindActive, "base"},
+	}
+	for _, tt := range tests {
+		info, err := reopened.Stat(ctx, tt.key)
+		if err != nil {
+			t.Errorf("Stat %s after reopening: %v", tt.key, err)
+			continue
+		}
+		if info.Kind != tt.kind || info.Parent != tt.parent {
+			t.Errorf("info of %s = %+v, want %s with parent %q", tt.key, info, tt.kind, tt.parent)
+		}
+	}
+	if got, _ := reopened.Usage(ctx, "base"); got != usage {
+		t.Errorf("usage of base = %+v, want %+v", got, usage)
+	}
+	if err := reopened.Remove(ctx, "base"); !errdefs.IsFailedPrecondition(err) {
+		t.Errorf("Remove of a parent after reopening = %v, want a failed precondition", err)
+	}
+
+	// IDs are not handed out again, the new snapshot gets a directory of its own
+	mounts, err := reopened.Prepare(ctx, "new", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(mounts[0].Source); len(entries) != 0 {
+		t.Errorf("new snapshot holds %d entries, want none", len(entries))
+	}
+	child, err := reopened.Mounts(ctx, "child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(child[0].Source, "file")); err != nil {
+		t.Errorf("contents of child lost: %v", err)
+	}
+
+	// no temporary metadata files are left behind
+	matches, _ := filepath.Glob(filepath.Join(root, ".metadata-*"))
+	if len(matches) != 0 {
+		t.Errorf("temporary files left: %v", matches)
+	}
+}
+
+func TestCorruptMetadata(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "metadata.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewNative(root); err == nil {
+		t.Error("NewNative with corrupt metadata succeeded")
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// readBundleSpec parses the bundle's config.json
+func readBundleSpec(bundle string) (*specs.Spec, error) {
+	data, err := os.ReadFile(filepath.Join(bundle, "config.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle spec: %w", err)
+	}
+	var spec specs.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// bundleRootfs reads the root path out of the bundle's config.json
+func bundleRootfs(bundle string) (string, error) {
+	spec, err := readBundleSpec(bundle)
+	if err != nil {
+		return "", err
+	}
+	if spec.Root == nil || spec.Root.Path == "" {
+		return "", errors.New("bundle spec has no root path")
+	}
+	if filepath.IsAbs(spec.Root.Path) {
+		return spec.Root.Path, nil
+	}
+	return filepath.Join(bundle, spec.Root.Path), nil
+}
+
+// writeBundleSpec replaces the bundle's config.json. The new spec is written
+// next to it and renamed into place, so runc never sees half a spec.
+func writeBundleSpec(bundle string, spec *specs.Spec) error {
+	data, err := json.MarshalIndent(spec, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(bundle, ".config-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write bundle spec: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write bundle spec: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write bundle spec: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle spec: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(bundle, "config.json")); err != nil {
+		return fmt.Errorf("failed to write bundle spec: %w", err)
+	}
+	return nil
+}
+
+// containerSpec decodes the spec a container was created with, nil when it
+// came without one
+func containerSpec(container *containerTask.Container) (*specs.Spec, error) {
+	if container.Spec == nil {
+		return nil, nil
+	}
+	var spec specs.Spec
+	if err := typeurl.UnmarshalTo(container.Spec, &spec); err != nil {
+		return nil, fmt.Errorf("invalid spec of type %q: %v: %w", container.Spec.TypeUrl, err, errdefs.ErrInvalidArgument)
+	}
+	return &spec, nil
+}
+
+// validateSpec checks a spec is complete before it goes anywhere near runc
+func validateSpec(spec *specs.Spec) error {
+	if err := oci.Validate(spec); err != nil {
+		return fmt.Errorf("invalid spec: %v: %w", err, errdefs.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// setBundleTerminal makes the bundle's process run on a terminal or not. runc
+// refuses to create a container whose spec disagrees with how its stdio is set up.
+func setBundleTerminal(bundle string, terminal bool) error {
+	spec, err := readBundleSpec(bundle)
+	if err != nil {
+		return err
+	}
+	if spec.Process == nil {
+		return errors.New("bundle spec has no process")
+	}
+	if spec.Process.Terminal == terminal {
+		return nil
+	}
+	spec.Process.Terminal = terminal
+	return writeBundleSpec(bundle, spec)
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	containerTask "kettle/api/kettle"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *ContainerTaskServiceImpl) ListContent(ctx context.Context, req *containerTask.ListContentRequest) (*containerTask.ListContentResponse, error) {
+	referenced, err := s.images.Referenced(ctx, s.containerImages()...)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []*containerTask.ContentInfo
+	if err := s.images.Content().Walk(ctx, func(info content.Info) error {
+		_, ok := referenced[info.Digest]
+		blobs = append(blobs, &containerTask.ContentInfo{
+			Digest:     info.Digest.String(),
+			Size:       info.Size,
+			CreatedAt:  timestamppb.New(info.CreatedAt),
+			Referenced: ok,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Digest < blobs[j].Digest })
+	return &containerTask.ListContentResponse{Blobs: blobs}, nil
+}
+
+func (s *ContainerTaskServiceImpl) DeleteContent(ctx context.Context, req *containerTask.DeleteContentRequest) (*emptypb.Empty, error) {
+	fmt.Println("function delete content called on grpc")
+	dgst, err := digest.Parse(req.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %w", req.Digest, errdefs.ErrInvalidArgument)
+	}
+	if err := s.images.Delete(ctx, dgst, req.Force, s.containerImages()...); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ContainerTaskServiceImpl) GarbageCollect(ctx context.Context, req *containerTask.GarbageCollectRequest) (*containerTask.GarbageCollectResponse, error) {
+	fmt.Println("function garbage collect called on grpc")
+	stats, err := s.images.GC(ctx, s.containerImages()...)
+	if err != nil {
+		return nil, err
+	}
+	resp := &containerTask.GarbageCollectResponse{
+		Freed:          stats.Freed,
+		AbortedIngests: int32(stats.Ingests),
+		Duration:       durationpb.New(stats.Duration),
+	}
+	for _, dgst := range stats.Removed {
+		resp.Removed = append(resp.Removed, dgst.String())
+	}
+	fmt.Printf("Garbage collection removed %d blobs in %s\n", len(stats.Removed), stats.Duration)
+	return resp, nil
+}
+
+// containerImages are the images containers were created from, kept for as
+// long as the containers are
+func (s *ContainerTaskServiceImpl) containerImages() []ocispec.Descriptor {
+	var roots []ocispec.Descriptor
+	for _, c := range s.store.List(nil) {
+		if c.Image == "" {
+			continue
+		}
+		if img, err := s.images.Get(c.Image); err == nil {
+			roots = append(roots, img.Target)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/archive"
+
+	"github.com/containerd/errdefs"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// copyChunkSize is how much tar data goes into a single stream message
+const copyChunkSize = 32 * 1024
+
+func (s *ContainerTaskServiceImpl) CopyTo(stream containerTask.Containers_CopyToServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	fmt.Println("function copy to called on grpc")
+	if first.Id == "" || first.Path == "" {
+		return fmt.Errorf("container id and path are required: %w", errdefs.ErrInvalidArgument)
+	}
+	root, err := s.containerRoot(first.Id)
+	if err != nil {
+		return err
+	}
+	r := &copyToReader{stream: stream, buf: first.Data}
+	if err := archive.CopyIn(r, root, first.Path); err != nil {
+		return fmt.Errorf("failed to copy to %s:%s: %w", first.Id, first.Path, err)
+	}
+	// drain whatever trails the archive so the client sees its send succeed
+	io.Copy(io.Discard, r)
+	return stream.SendAndClose(&emptypb.Empty{})
+}
+
+func (s *ContainerTaskServiceImpl) CopyFrom(req *containerTask.CopyFromRequest, stream containerTask.Containers_CopyFromServer) error {
+	fmt.Println("function copy from called on grpc")
+	if req.Id == "" || req.Path == "" {
+		return fmt.Errorf("container id and path are required: %w", errdefs.ErrInvalidArgument)
+	}
+	root, err := s.containerRoot(req.Id)
+	if err != nil {
+		return err
+	}
+	w := &copyFromWriter{stream: stream}
+	if err := archive.CopyOut(w, root, req.Path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s:%s: %w", req.Id, req.Path, errdefs.ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// containerRoot is the host path of the container's root filesystem. A running
+// container is reached through its init process so that its mounts are
+// visible too, otherwise the bundle's rootfs is used.
+func (s *ContainerTaskServiceImpl) containerRoot(id string) (string, error) {
+	container, err := s.store.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if resp, err := probeShim(id); err == nil && resp.TaskPid != 0 {
+		return fmt.Sprintf("/proc/%d/root", resp.TaskPid), nil
+	}
+	return bundleRootfs(container.Bundle)
+}
+
+type copyToReader struct {
+	stream containerTask.Containers_CopyToServer
+	buf    []byte
+}
+
+func (r *copyToReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = msg.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type copyFromWriter struct {
+	stream containerTask.Containers_CopyFromServer
+}
+
+func (w *copyFromWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		end := min(written+copyChunkSize, len(p))
+		if err := w.stream.Send(&containerTask.CopyData{Data: p[written:end]}); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return len(p), nil
+}
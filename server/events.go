@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	containerTask "kettle/api/kettle"
+	shimTask "kettle/api/shim"
+
+	"github.com/containerd/errdefs"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventsSocketPath is where the daemon takes the events its shims forward
+const eventsSocketPath = "/run/kettle/events.sock"
+
+// Event types, the shims publish start, exit, oom and backoff
+const (
+	eventCreate    = "create"
+	eventStart     = "start"
+	eventExit      = "exit"
+	eventOOM       = "oom"
+	eventBackoff   = "backoff"
+	eventDelete    = "delete"
+	eventShimStart = "shim-start"
+	eventShimExit  = "shim-exit"
+	eventPull      = "pull"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is dropped, so that a stuck client does not hold up everyone else
+const subscriberBuffer = 256
+
+// eventBus hands the daemon's events to the subscribers whose filters match
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	events chan *containerTask.Event
+	filter eventFilter
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: map[*subscriber]struct{}{}}
+}
+
+// publish sends an event that happened just now
+func (b *eventBus) publish(typ, containerID string, attributes map[string]string) {
+	b.send(&containerTask.Event{
+		Timestamp:   timestamppb.Now(),
+		Type:        typ,
+		ContainerId: containerID,
+		Attributes:  attributes,
+	})
+}
+
+func (b *eventBus) send(event *containerTask.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		if !sub.filter.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// the subscriber sees its channel closed and gives up
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe returns the channel the events matching filter are delivered on
+// and a function to stop receiving them. The channel is closed when the
+// subscriber falls too far behind.
+func (b *eventBus) subscribe(filter eventFilter) (<-chan *containerTask.Event, func()) {
+	sub := &subscriber{
+		events: make(chan *containerTask.Event, subscriberBuffer),
+		filter: filter,
+	}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[sub]; ok {
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// eventFilter holds the values accepted for each key, an empty filter takes
+// every event
+type eventFilter map[string][]string
+
+// parseEventFilters reads "key=value" filters, key being type, container or
+// the name of an attribute
+func parseEventFilters(filters []string) (eventFilter, error) {
+	filter := eventFilter{}
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid event filter %q, expected key=value: %w", f, errdefs.ErrInvalidArgument)
+		}
+		filter[key] = append(filter[key], value)
+	}
+	return filter, nil
+}
+
+func (f eventFilter) match(event *containerTask.Event) bool {
+	for key, values := range f {
+		var actual string
+		switch key {
+		case "type":
+			actual = event.Type
+		case "container":
+			actual = event.ContainerId
+		default:
+			v, ok := event.Attributes[key]
+			if !ok {
+				return false
+			}
+			actual = v
+		}
+		if !slices.Contains(values, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ContainerTaskServiceImpl) Subscribe(req *containerTask.SubscribeRequest, stream containerTask.Containers_SubscribeServer) error {
+	fmt.Println("function subscribe called on grpc")
+	filter, err := parseEventFilters(req.Filters)
+	if err != nil {
+		return err
+	}
+	events, cancel := s.events.subscribe(filter)
+	defer cancel()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return fmt.Errorf("subscriber fell behind: %w", errdefs.ErrResourceExhausted)
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// shimEvents takes the task events forwarded by the shims and puts them on
+// the daemon's bus
+type shimEvents struct {
+	bus *eventBus
+}
+
+func (e *shimEvents) Forward(ctx context.Context, req *shimTask.TaskEvent) (*emptypb.Empty, error) {
+	timestamp := req.Timestamp
+	if timestamp == nil {
+		timestamp = timestamppb.Now()
+	}
+	e.bus.send(&containerTask.Event{
+		Timestamp:   timestamp,
+		Type:        req.Type,
+		ContainerId: req.Id,
+		Attributes:  req.Attributes,
+	})
+	return &emptypb.Empty{}, nil
+}
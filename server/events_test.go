@@ -0,0 +1,77 @@
+package server
+
+import (
+	"testing"
+
+	containerTask "kettle/api/kettle"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestEventFilterMatch(t *testing.T) {
+	exit := &containerTask.Event{
+		Type:        eventExit,
+		ContainerId: "web",
+		Attributes:  map[string]string{"exec_id": "e1", "exit_status": "0"},
+	}
+	tests := []struct {
+		filters []string
+		want    bool
+	}{
+		{nil, true},
+		{[]string{"type=exit"}, true},
+		{[]string{"type=start"}, false},
+		// values of the same key are alternatives
+		{[]string{"type=start", "type=exit"}, true},
+		// different keys all have to match
+		{[]string{"type=exit", "container=web"}, true},
+		{[]string{"type=exit", "container=db"}, false},
+		{[]string{"exec_id=e1"}, true},
+		{[]string{"exec_id=e2"}, false},
+		{[]string{"image=alpine"}, false},
+		{[]string{"exit_status="}, false},
+	}
+	for _, tt := range tests {
+		filter, err := parseEventFilters(tt.filters)
+		if err != nil {
+			t.Fatalf("parseEventFilters(%q): %v", tt.filters, err)
+		}
+		if got := filter.match(exit); got != tt.want {
+			t.Errorf("%q matches the exit of web = %v, want %v", tt.filters, got, tt.want)
+		}
+	}
+}
+
+func TestParseEventFiltersInvalid(t *testing.T) {
+	for _, f := range []string{"type", "=exit"} {
+		if _, err := parseEventFilters([]string{f}); !errdefs.IsInvalidArgument(err) {
+			t.Errorf("parseEventFilters(%q) = %v, want an invalid argument", f, err)
+		}
+	}
+}
+
+func TestEventBusSlowSubscriber(t *testing.T) {
+	bus := newEventBus()
+	events, cancel := bus.subscribe(eventFilter{"type": {eventOOM}})
+	defer cancel()
+
+	// events the filter drops do not count against the buffer
+	for i := 0; i < subscriberBuffer*2; i++ {
+		bus.publish(eventStart, "web", nil)
+	}
+	bus.publish(eventOOM, "web", nil)
+	if event := <-events; event.Type != eventOOM || event.ContainerId != "web" {
+		t.Fatalf("received %v", event)
+	}
+
+	// a subscriber that falls behind is dropped
+	for i := 0; i <= subscriberBuffer; i++ {
+		bus.publish(eventOOM, "web", nil)
+	}
+	for i := 0; i < subscriberBuffer; i++ {
+		<-events
+	}
+	if _, ok := <-events; ok {
+		t.Error("the events of a subscriber that fell behind are not closed")
+	}
+}
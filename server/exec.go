@@ -0,0 +1,274 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	containerTask "kettle/api/kettle"
+	shimTask "kettle/api/shim"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/errdefs"
+	"github.com/moby/sys/signal"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// defaultStopTimeout is how long Stop waits for a container before killing it
+const defaultStopTimeout = 10 * time.Second
+
+// killTimeout is how long Stop waits for a container it sent SIGKILL
+const killTimeout = 10 * time.Second
+
+// connectContainerShim connects to the shim of a container known to the store
+func (s *ContainerTaskServiceImpl) connectContainerShim(id string) (*shimClient, error) {
+	if id == "" {
+		return nil, fmt.Errorf("container id is required: %w", errdefs.ErrInvalidArgument)
+	}
+	if _, err := s.store.Get(id); err != nil {
+		return nil, err
+	}
+	return connectShim(id)
+}
+
+func (s *ContainerTaskServiceImpl) Exec(ctx context.Context, req *containerTask.ExecRequest) (*containerTask.ExecResponse, error) {
+	fmt.Println("function exec called on grpc")
+	if req.Process == nil || len(req.Process.Args) == 0 {
+		return nil, fmt.Errorf("exec needs a command to run: %w", errdefs.ErrInvalidArgument)
+	}
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	resp, err := shim.Exec(ctx, &shimTask.ExecProcessRequest{
+		Id:     req.ContainerId,
+		ExecId: req.ExecId,
+		Process: &shimTask.ProcessSpec{
+			Args:     req.Process.Args,
+			Env:      req.Process.Env,
+			Cwd:      req.Process.Cwd,
+			User:     req.Process.User,
+			Terminal: req.Process.Terminal,
+		},
+		Stdin: req.Stdin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec in container: %w", err)
+	}
+	return &containerTask.ExecResponse{ExecId: resp.ExecId}, nil
+}
+
+func (s *ContainerTaskServiceImpl) Wait(ctx context.Context, req *containerTask.WaitRequest) (*containerTask.WaitResponse, error) {
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	resp, err := shim.Wait(ctx, &shimTask.WaitRequest{Id: req.ContainerId, ExecId: req.ExecId})
+	if err != nil {
+		return nil, err
+	}
+	if req.ExecId == "" {
+		// the container may have been deleted while we waited
+		if _, err := s.setStatus(req.ContainerId, statusExited); err != nil && !errdefs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return &containerTask.WaitResponse{ExitStatus: resp.ExitStatus, ExitedAt: resp.ExitedAt}, nil
+}
+
+func (s *ContainerTaskServiceImpl) Kill(ctx context.Context, req *containerTask.KillRequest) (*emptypb.Empty, error) {
+	fmt.Println("function kill called on grpc")
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	if _, err := shim.Kill(ctx, &shimTask.KillRequest{
+		Id:     req.ContainerId,
+		ExecId: req.ExecId,
+		Signal: req.Signal,
+		All:    req.All,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to signal process: %w", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Stop gives the container the chance to shut down cleanly before killing it
+func (s *ContainerTaskServiceImpl) Stop(ctx context.Context, req *containerTask.StopRequest) (*containerTask.WaitResponse, error) {
+	fmt.Println("function stop called on grpc")
+	container, err := s.store.Get(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	shim, err := connectShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+	timeout := defaultStopTimeout
+	if req.Timeout != nil {
+		timeout = req.Timeout.AsDuration()
+	}
+	sig, err := stopSignal(container.Bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	// a stopped container stays down whatever its restart policy says
+	if _, err := shim.Update(ctx, &shimTask.UpdateTaskRequest{
+		Id:            req.ContainerId,
+		RestartPolicy: &shimTask.RestartPolicy{Name: RestartNever},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to suspend restart policy: %w", err)
+	}
+	wait := &shimTask.WaitRequest{Id: req.ContainerId}
+	// the container may be gone already, in which case Wait returns right away
+	if _, err := shim.Kill(ctx, &shimTask.KillRequest{Id: req.ContainerId, Signal: uint32(sig)}); err != nil {
+		fmt.Printf("Failed to send %s to %s: %v\n", unix.SignalName(sig), req.ContainerId, err)
+	}
+	if container.Status == statusPaused {
+		// a frozen container cannot act on its signal
+		if _, err := shim.Resume(ctx, &shimTask.ResumeRequest{Id: req.ContainerId}); err != nil {
+			fmt.Printf("Failed to resume %s: %v\n", req.ContainerId, err)
+		}
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	resp, err := shim.Wait(waitCtx, wait)
+	cancel()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		fmt.Printf("Container %s did not stop within %s, killing it\n", req.ContainerId, timeout)
+		if _, err := shim.Kill(ctx, &shimTask.KillRequest{Id: req.ContainerId, Signal: uint32(unix.SIGKILL), All: true}); err != nil {
+			fmt.Printf("Failed to kill %s: %v\n", req.ContainerId, err)
+		}
+		killCtx, cancel := context.WithTimeout(ctx, killTimeout)
+		resp, err = shim.Wait(killCtx, wait)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("container %s did not exit after SIGKILL: %w", req.ContainerId, err)
+		}
+	}
+	// the container is down for good, the policy it was created with applies
+	// again should it be started later
+	if _, err := shim.Update(ctx, &shimTask.UpdateTaskRequest{
+		Id:            req.ContainerId,
+		RestartPolicy: toShimRestartPolicy(container.RestartPolicy),
+	}); err != nil {
+		fmt.Printf("Failed to restore the restart policy of %s: %v\n", req.ContainerId, err)
+	}
+	if _, err := s.setStatus(req.ContainerId, statusExited); err != nil {
+		return nil, err
+	}
+	return &containerTask.WaitResponse{ExitStatus: resp.ExitStatus, ExitedAt: resp.ExitedAt}, nil
+}
+
+// stopSignal is the signal a container asks to be stopped with through the
+// OCI image annotation in its bundle, SIGTERM by default
+func stopSignal(bundle string) (unix.Signal, error) {
+	spec, err := readBundleSpec(bundle)
+	if err != nil {
+		return 0, err
+	}
+	name, ok := spec.Annotations[oci.AnnotationStopSignal]
+	if !ok {
+		return unix.SIGTERM, nil
+	}
+	sig, err := signal.ParseSignal(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid stop signal %q: %w", name, errdefs.ErrInvalidArgument)
+	}
+	return sig, nil
+}
+
+func (s *ContainerTaskServiceImpl) ResizePty(ctx context.Context, req *containerTask.ResizePtyRequest) (*emptypb.Empty, error) {
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	if _, err := shim.ResizePty(ctx, &shimTask.ResizePtyRequest{
+		Id:     req.ContainerId,
+		ExecId: req.ExecId,
+		Width:  req.Width,
+		Height: req.Height,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to resize terminal: %w", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Attach relays a client's attach stream to the shim and back
+func (s *ContainerTaskServiceImpl) Attach(stream containerTask.Containers_AttachServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	fmt.Println("function attach called on grpc")
+	shim, err := s.connectContainerShim(first.ContainerId)
+	if err != nil {
+		return err
+	}
+	defer shim.Close()
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	shimStream, err := shim.Attach(ctx)
+	if err != nil {
+		return err
+	}
+	if err := shimStream.Send(toShimAttachRequest(first)); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					shimStream.CloseSend()
+					return
+				}
+				// the client is gone, take the shim stream down with it
+				cancel()
+				return
+			}
+			if err := shimStream.Send(toShimAttachRequest(req)); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		resp, err := shimStream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&containerTask.AttachResponse{Stdout: resp.Stdout, Stderr: resp.Stderr}); err != nil {
+			return err
+		}
+	}
+}
+
+func toShimAttachRequest(req *containerTask.AttachRequest) *shimTask.AttachRequest {
+	return &shimTask.AttachRequest{
+		Id:         req.ContainerId,
+		ExecId:     req.ExecId,
+		Stdin:      req.Stdin,
+		CloseStdin: req.CloseStdin,
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/errdefs"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestStopSignal(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       unix.Signal
+		invalid    bool
+	}{
+		{"", unix.SIGTERM, false},
+		{"SIGHUP", unix.SIGHUP, false},
+		{"SIGBOGUS", 0, true},
+	}
+	for _, tt := range tests {
+		bundle := t.TempDir()
+		spec := oci.DefaultSpec()
+		if tt.annotation != "" {
+			spec.Annotations = map[string]string{oci.AnnotationStopSignal: tt.annotation}
+		}
+		if err := writeBundleSpec(bundle, spec); err != nil {
+			t.Fatal(err)
+		}
+		sig, err := stopSignal(bundle)
+		if tt.invalid {
+			if !errdefs.IsInvalidArgument(err) {
+				t.Errorf("stopSignal(%q) = %v, want invalid argument", tt.annotation, err)
+			}
+			continue
+		}
+		if err != nil || sig != tt.want {
+			t.Errorf("stopSignal(%q) = %v, %v, want %v", tt.annotation, sig, err, tt.want)
+		}
+	}
+}
+
+func TestStopExited(t *testing.T) {
+	container := &containerTask.Container{
+		ID:            fmt.Sprintf("stop-%d", os.Getpid()),
+		Status:        statusRunning,
+		RestartPolicy: &containerTask.RestartPolicy{Name: RestartOnFailure},
+	}
+	s := newUpdateTest(t, container)
+	// the container exited behind the daemon's back, the stop signal cannot
+	// be delivered any more
+	shim := newTaskService()
+	shim.id = container.ID
+	shim.state = stateStopped
+	shim.restarts.lastExitStatus = 143
+	shim.restarts.lastExitAt = time.Now()
+	close(shim.exited)
+	serveShim(t, container.ID, shim)
+
+	start := time.Now()
+	resp, err := s.Stop(context.Background(), &containerTask.StopRequest{
+		ContainerId: container.ID,
+		Timeout:     durationpb.New(5 * time.Second),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Stop of an exited container took %s", elapsed)
+	}
+	if resp.ExitStatus != 143 || resp.ExitedAt == nil {
+		t.Errorf("Stop = %+v, want the exit of the container", resp)
+	}
+	stored, err := s.store.Get(container.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != statusExited {
+		t.Errorf("status = %s, want %s", stored.Status, statusExited)
+	}
+	// the restart policy suspended for the stop is back
+	if shim.policy.name != RestartOnFailure {
+		t.Errorf("shim policy = %s, want %s", shim.policy.name, RestartOnFailure)
+	}
+
+	if _, err := s.Stop(context.Background(), &containerTask.StopRequest{ContainerId: "missing"}); !errdefs.IsNotFound(err) {
+		t.Errorf("Stop of a missing container = %v, want not found", err)
+	}
+}
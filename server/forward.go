@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	task "kettle/api/shim"
+
+	"github.com/containerd/ttrpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// forwardQueue is how many events the shim holds on to while the daemon is
+// slow to take them
+const forwardQueue = 128
+
+// eventForwarder sends the shim's task events to the daemon. Events are
+// queued so that the monitor never waits on the daemon, and dropped when the
+// daemon is not around to take them, the shim outlives it after all.
+type eventForwarder struct {
+	queue chan *task.TaskEvent
+}
+
+func newEventForwarder() *eventForwarder {
+	f := &eventForwarder{queue: make(chan *task.TaskEvent, forwardQueue)}
+	go f.run()
+	return f
+}
+
+// publish queues an event that happened just now to the container id
+func (f *eventForwarder) publish(typ, id string, attributes map[string]string) {
+	event := &task.TaskEvent{
+		Timestamp:  timestamppb.Now(),
+		Type:       typ,
+		Id:         id,
+		Attributes: attributes,
+	}
+	select {
+	case f.queue <- event:
+	default:
+		log.Printf("dropping %s event of container %s, the daemon is not keeping up", typ, id)
+	}
+}
+
+func (f *eventForwarder) run() {
+	var client *ttrpc.Client
+	for event := range f.queue {
+		// a daemon that restarted leaves a dead connection behind, so a
+		// failed forward is tried once more on a new one
+		for attempt := 0; attempt < 2; attempt++ {
+			if client == nil {
+				conn, err := net.DialTimeout("unix", eventsSocketPath, 2*time.Second)
+				if err != nil {
+					log.Printf("failed to forward %s event of container %s: %v", event.Type, event.Id, err)
+					break
+				}
+				client = ttrpc.NewClient(conn)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, err := task.NewEventsClient(client).Forward(ctx, event)
+			cancel()
+			if err == nil {
+				break
+			}
+			client.Close()
+			client = nil
+			if attempt == 1 {
+				log.Printf("failed to forward %s event of container %s: %v", event.Type, event.Id, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/image"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *ContainerTaskServiceImpl) Pull(ctx context.Context, req *containerTask.PullRequest) (*containerTask.PullResponse, error) {
+	fmt.Println("function pull called on grpc")
+	if req.Ref == "" {
+		return nil, fmt.Errorf("image reference is required: %w", errdefs.ErrInvalidArgument)
+	}
+	img, err := s.images.Pull(ctx, req.Ref)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Image pulled:", img.Name, img.Target.Digest)
+	s.events.publish(eventPull, "", map[string]string{
+		"image":  img.Name,
+		"digest": img.Target.Digest.String(),
+	})
+	return &containerTask.PullResponse{Image: toImage(img)}, nil
+}
+
+// imageSpec completes the container's spec from the config of its image,
+// pulling the image first if needed. Without a spec the default one is used
+// with a writable rootfs. The container's image and spec are updated to the
+// normalized name and the completed spec.
+func (s *ContainerTaskServiceImpl) imageSpec(ctx context.Context, container *containerTask.Container, spec *specs.Spec) (*specs.Spec, error) {
+	img, err := s.images.Get(container.Image)
+	if errdefs.IsNotFound(err) {
+		img, err = s.images.Pull(ctx, container.Image)
+	}
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.images.Config(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		spec = oci.DefaultSpec()
+		spec.Hostname = container.ID
+		spec.Root.Readonly = false
+		// the command, working directory and environment come from the image
+		spec.Process.Args = nil
+		spec.Process.Cwd = ""
+		spec.Process.Env = nil
+	}
+	apply := func(rootfs string) error {
+		if err := oci.WithImageConfig(config.Config, rootfs)(spec); err != nil {
+			return fmt.Errorf("invalid spec for image %s: %v: %w", img.Name, err, errdefs.ErrInvalidArgument)
+		}
+		return nil
+	}
+	if config.Config.User != "" {
+		// user and group names are looked up in the image's own files
+		err = s.withImageRootfs(ctx, container, img, apply)
+	} else {
+		err = apply("")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if container.Spec, err = typeurl.MarshalAnyToProto(spec); err != nil {
+		return nil, err
+	}
+	container.Image = img.Name
+	return spec, nil
+}
+
+func toImage(img image.Image) *containerTask.Image {
+	return &containerTask.Image{
+		Name:      img.Name,
+		Digest:    img.Target.Digest.String(),
+		MediaType: img.Target.MediaType,
+		Size:      img.Target.Size,
+		CreatedAt: timestamppb.New(img.CreatedAt),
+	}
+}
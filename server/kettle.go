@@ -5,61 +5,706 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	containerTask "kettle/api/kettle"
 	shimTask "kettle/api/shim"
+	"kettle/pkg/image"
+	"kettle/pkg/oci"
+	"kettle/pkg/snapshot"
+
+	"github.com/containerd/errdefs"
+	cni "github.com/containerd/go-cni"
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Container statuses recorded in the metadata store
+const (
+	statusCreated = "created"
+	statusRunning = "running"
+	statusPaused  = "paused"
+	statusExited  = "exited"
 )
 
+// containerStatus maps the states of a shim onto the statuses recorded here
+var containerStatus = map[taskState]string{
+	stateCreated: statusCreated,
+	stateRunning: statusRunning,
+	statePaused:  statusPaused,
+	stateStopped: statusExited,
+}
+
+// defaultRuntime is recorded for containers that do not ask for a runtime
+const defaultRuntime = "runc"
+
+// container IDs end up in paths and runc state, keep them boring
+var validID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,127}$`)
+
 type ContainerTaskServiceImpl struct {
 	containerTask.UnimplementedContainersServer
+	config    Config
+	store     *containerStore
+	images    *image.Store
+	snapshots snapshot.Snapshotter
+	events    *eventBus
+	network   cni.CNI
+}
+
+// NewContainerTaskService loads the container records kept under config.Root and
+// re-adopts the containers whose shims outlived the previous daemon
+func NewContainerTaskService(config Config) (*ContainerTaskServiceImpl, error) {
+	store, err := newContainerStore(config.Root)
+	if err != nil {
+		return nil, err
+	}
+	images, err := image.NewStore(filepath.Join(config.Root, "images"))
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := newSnapshotter(config)
+	if err != nil {
+		return nil, err
+	}
+	network, err := newNetwork(config)
+	if err != nil {
+		return nil, err
+	}
+	s := &ContainerTaskServiceImpl{
+		config:    config,
+		store:     store,
+		images:    images,
+		snapshots: snapshots,
+		events:    newEventBus(),
+		network:   network,
+	}
+	s.recoverContainers()
+	return s, nil
 }
 
 func (s *ContainerTaskServiceImpl) Create(ctx context.Context, req *containerTask.CreateContainerRequest) (*containerTask.CreateContainerResponse, error) {
 	fmt.Println("function create called on grpc")
-	err := createContainer(req.Container.Bundle, req.Container.ID)
+	container := req.Container
+	if container == nil || container.ID == "" || (container.Bundle == "" && container.Image == "") {
+		return nil, fmt.Errorf("container id and a bundle or image are required: %w", errdefs.ErrInvalidArgument)
+	}
+	if !validID.MatchString(container.ID) {
+		return nil, fmt.Errorf("invalid container id %q: %w", container.ID, errdefs.ErrInvalidArgument)
+	}
+	spec, err := containerSpec(container)
+	if err != nil {
+		return nil, err
+	}
+	if container.Image != "" {
+		if spec, err = s.imageSpec(ctx, container, spec); err != nil {
+			return nil, err
+		}
+		if container.Bundle == "" {
+			container.Bundle = filepath.Join(s.store.containerDir(container.ID), "bundle")
+		}
+	}
+	if spec != nil {
+		if err := validateSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+	now := timestamppb.Now()
+	container.CreatedAt = now
+	container.UpdatedAt = now
+	if container.Runtime == "" {
+		container.Runtime = defaultRuntime
+	}
+	if err := s.store.Create(container); err != nil {
+		return nil, err
+	}
+	address, err := s.createTask(ctx, container, spec)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create container: %w", err)
+		// the shim may be up already, it must not outlive the record
+		cleanupCtx := context.WithoutCancel(ctx)
+		if err := removeTask(cleanupCtx, container.ID, true); err != nil {
+			fmt.Println("failed to remove shim of failed container:", container.ID, err)
+		}
+		s.removeNetwork(cleanupCtx, container)
+		s.removeRootfs(cleanupCtx, container)
+		s.store.Delete(container.ID)
+		return nil, err
 	}
-	return &containerTask.CreateContainerResponse{}, nil
+	created, err := s.store.Update(container.ID, func(c *containerTask.Container) error {
+		c.Status = statusCreated
+		c.ShimAddress = address
+		c.Network = container.Network
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(eventCreate, created.ID, map[string]string{
+		"bundle": created.Bundle,
+		"image":  created.Image,
+	})
+	return &containerTask.CreateContainerResponse{Container: created}, nil
+}
+
+// createTask prepares the bundle and has the container's shim create it,
+// returning the address the shim serves on
+func (s *ContainerTaskServiceImpl) createTask(ctx context.Context, container *containerTask.Container, spec *specs.Spec) (string, error) {
+	if err := createBundle(container.Bundle, spec); err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+	if container.Image != "" {
+		if err := s.mountRootfs(ctx, container); err != nil {
+			return "", fmt.Errorf("failed to create container: %w", err)
+		}
+	}
+	if err := setBundleTerminal(container.Bundle, container.Terminal); err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+	if err := s.setupNetwork(ctx, container); err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+	shimPid, address, err := runShim(container.ID, s.events, func(pid uint32, status int) {
+		s.shimExited(container.ID, pid, status)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+	fmt.Printf("Shim for %s running with pid %d at %s\n", container.ID, shimPid, address)
+	shim, err := connectShim(container.ID)
+	if err != nil {
+		return "", err
+	}
+	defer shim.Close()
+
+	createReq := shimTask.CreateTaskRequest{
+		Id:            container.ID,
+		Bundle:        container.Bundle,
+		RestartPolicy: toShimRestartPolicy(container.RestartPolicy),
+		LogPath:       s.logPath(container.ID),
+		LogMaxSize:    s.config.LogMaxSize,
+		LogMaxFiles:   int32(s.config.LogMaxFiles),
+		Terminal:      container.Terminal,
+		OpenStdin:     container.Stdin,
+	}
+	if _, err := shim.Create(ctx, &createReq); err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+	return address, nil
+}
+
+func (s *ContainerTaskServiceImpl) Get(ctx context.Context, req *containerTask.GetContainerRequest) (*containerTask.GetContainerResponse, error) {
+	container, err := s.store.Get(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	// a container created from a bundle has its spec there and not in the
+	// record, clients may not see the daemon's filesystem to read it
+	if container.Spec == nil {
+		if spec, err := readBundleSpec(container.Bundle); err == nil {
+			if container.Spec, err = typeurl.MarshalAnyToProto(spec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &containerTask.GetContainerResponse{Container: container}, nil
+}
+
+func (s *ContainerTaskServiceImpl) List(ctx context.Context, req *containerTask.ListContainersRequest) (*containerTask.ListContainersResponse, error) {
+	return &containerTask.ListContainersResponse{Containers: s.store.List(req.Labels)}, nil
+}
+
+func (s *ContainerTaskServiceImpl) Update(ctx context.Context, req *containerTask.UpdateContainerRequest) (_ *containerTask.UpdateContainerResponse, retErr error) {
+	fmt.Println("function update called on grpc")
+	if req.Container == nil || req.Container.ID == "" {
+		return nil, fmt.Errorf("container id is required: %w", errdefs.ErrInvalidArgument)
+	}
+	paths := req.UpdateMask.GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"labels", "restart_policy"}
+		if req.Resources != nil {
+			paths = append(paths, "resources")
+		}
+	}
+	if err := checkUpdatePaths(paths); err != nil {
+		return nil, err
+	}
+
+	container, err := s.store.Get(req.Container.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// resources are applied first, a container the limits cannot be applied
+	// to is left unchanged, and so is one a later step fails for
+	var resources *specs.LinuxResources
+	if slices.Contains(paths, "resources") {
+		var previous *specs.Spec
+		if resources, previous, err = s.updateResources(ctx, container, req.Resources); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if retErr != nil {
+				if err := applyResources(ctx, container, previous); err != nil {
+					fmt.Println("Failed to restore the resources of", container.ID, err)
+				}
+			}
+		}()
+	}
+
+	// the shim enforces the restart policy, the record only takes a policy
+	// the shim has taken
+	if slices.Contains(paths, "restart_policy") {
+		if err := updateRestartPolicy(ctx, container.ID, req.Container.RestartPolicy); err != nil {
+			return nil, fmt.Errorf("failed to update restart policy: %w", err)
+		}
+		defer func() {
+			if retErr != nil {
+				if err := updateRestartPolicy(ctx, container.ID, container.RestartPolicy); err != nil {
+					fmt.Printf("Failed to restore the restart policy of %s: %v\n", container.ID, err)
+				}
+			}
+		}()
+	}
+
+	updated, err := s.store.Update(req.Container.ID, func(c *containerTask.Container) error {
+		for _, path := range paths {
+			switch {
+			case path == "labels":
+				c.Labels = req.Container.Labels
+			case strings.HasPrefix(path, "labels."):
+				key := strings.TrimPrefix(path, "labels.")
+				if v, ok := req.Container.Labels[key]; ok {
+					if c.Labels == nil {
+						c.Labels = map[string]string{}
+					}
+					c.Labels[key] = v
+				} else {
+					delete(c.Labels, key)
+				}
+			case path == "restart_policy":
+				c.RestartPolicy = req.Container.RestartPolicy
+			case path == "resources":
+				// a container created without a spec only has the bundle's
+				if c.Spec == nil {
+					continue
+				}
+				spec, err := containerSpec(c)
+				if err != nil {
+					return err
+				}
+				if err := oci.WithResourceUpdates(resources)(spec); err != nil {
+					return err
+				}
+				if c.Spec, err = typeurl.MarshalAnyToProto(spec); err != nil {
+					return err
+				}
+			}
+		}
+		c.UpdatedAt = timestamppb.Now()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &containerTask.UpdateContainerResponse{Container: updated}, nil
+}
+
+// updateRestartPolicy hands the container's shim a new restart policy, no
+// policy at all being the default one
+func updateRestartPolicy(ctx context.Context, id string, policy *containerTask.RestartPolicy) error {
+	shim, err := connectShim(id)
+	if err != nil {
+		return err
+	}
+	defer shim.Close()
+	p := toShimRestartPolicy(policy)
+	if p == nil {
+		p = &shimTask.RestartPolicy{}
+	}
+	_, err = shim.Update(ctx, &shimTask.UpdateTaskRequest{Id: id, RestartPolicy: p})
+	return err
+}
+
+// checkUpdatePaths refuses an update mask naming a field Update cannot change
+func checkUpdatePaths(paths []string) error {
+	for _, path := range paths {
+		switch {
+		case path == "labels", strings.HasPrefix(path, "labels."), path == "restart_policy", path == "resources":
+		default:
+			return fmt.Errorf("cannot update field %q: %w", path, errdefs.ErrInvalidArgument)
+		}
+	}
+	return nil
+}
+
+// updateResources changes the cgroup limits of the container: the running
+// container through its shim, later starts through the bundle's spec. It
+// returns the update and the bundle's spec from before it, for applyResources
+// to go back to.
+func (s *ContainerTaskServiceImpl) updateResources(ctx context.Context, container *containerTask.Container, resources *anypb.Any) (*specs.LinuxResources, *specs.Spec, error) {
+	if resources == nil {
+		return nil, nil, fmt.Errorf("no resources to update: %w", errdefs.ErrInvalidArgument)
+	}
+	v, err := typeurl.UnmarshalAny(resources)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid resources: %v: %w", err, errdefs.ErrInvalidArgument)
+	}
+	update, ok := v.(*specs.LinuxResources)
+	if !ok {
+		return nil, nil, fmt.Errorf("resources are a %T, not linux resources: %w", v, errdefs.ErrInvalidArgument)
+	}
+	previous, err := readBundleSpec(container.Bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	spec, err := readBundleSpec(container.Bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := oci.WithResourceUpdates(update)(spec); err != nil {
+		return nil, nil, err
+	}
+	if err := oci.ValidateResources(spec.Linux.Resources); err != nil {
+		return nil, nil, fmt.Errorf("invalid resources: %v: %w", err, errdefs.ErrInvalidArgument)
+	}
+	if err := applyResources(ctx, container, spec); err != nil {
+		return nil, nil, err
+	}
+	return update, previous, nil
+}
+
+// applyResources gives the container the cgroup limits of spec and writes
+// spec to its bundle
+func applyResources(ctx context.Context, container *containerTask.Container, spec *specs.Spec) error {
+	// the shim of an exited container may be gone, the spec is enough then
+	if container.Status != statusExited && spec.Linux != nil && spec.Linux.Resources != nil {
+		resources, err := typeurl.MarshalAnyToProto(spec.Linux.Resources)
+		if err != nil {
+			return err
+		}
+		shim, err := connectShim(container.ID)
+		if err != nil {
+			return err
+		}
+		defer shim.Close()
+		if _, err := shim.Update(ctx, &shimTask.UpdateTaskRequest{Id: container.ID, Resources: resources}); err != nil {
+			return err
+		}
+	}
+	return writeBundleSpec(container.Bundle, spec)
+}
+
+func (s *ContainerTaskServiceImpl) Delete(ctx context.Context, req *containerTask.DeleteContainerRequest) (*emptypb.Empty, error) {
+	fmt.Println("function delete called on grpc")
+	container, err := s.store.Get(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := removeTask(ctx, container.ID, req.Force); err != nil {
+		return nil, err
+	}
+	// the rootfs has to be unmounted before the bundle goes, or the removal
+	// would reach into the snapshot
+	if err := s.removeRootfs(ctx, container); err != nil {
+		return nil, err
+	}
+	if err := s.removeNetwork(ctx, container); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(container.Bundle); err != nil {
+		return nil, fmt.Errorf("failed to remove bundle: %w", err)
+	}
+	// takes the container's logs along
+	if err := s.store.Delete(container.ID); err != nil {
+		return nil, err
+	}
+	fmt.Println("Container deleted:", container.ID)
+	s.events.publish(eventDelete, container.ID, nil)
+	return &emptypb.Empty{}, nil
+}
+
+// removeTask has the container's shim delete it and exit, and removes the
+// shim's state directory. The shim may already be gone along with the
+// container, that is fine.
+func removeTask(ctx context.Context, id string, force bool) error {
+	if shim, err := connectShim(id); err == nil {
+		err := deleteTask(ctx, shim, id, force)
+		shim.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(filepath.Dir(shimSocketPath(id))); err != nil {
+		return fmt.Errorf("failed to remove shim state: %w", err)
+	}
+	return nil
+}
+
+// deleteTask has the shim delete its container and exit. A running container
+// is refused unless force is set.
+func deleteTask(ctx context.Context, shim *shimClient, id string, force bool) error {
+	deleteCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	// a shim whose container was never created or already deleted has nothing to do
+	if _, err := shim.Delete(deleteCtx, &shimTask.DeleteRequest{Id: id, Force: force}); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to delete container: %w", err)
+	}
+	if _, err := shim.Shutdown(deleteCtx, &shimTask.ShutdownRequest{Id: id}); err != nil {
+		fmt.Println("Failed to shut down shim for", id, err)
+	}
+	return nil
 }
 
 func (s *ContainerTaskServiceImpl) Start(ctx context.Context, req *containerTask.StartRequest) (*containerTask.StartResponse, error) {
 	fmt.Println("function start called on grpc")
-	pid, err := runShim(req.ContainerId)
+	shim, err := connectShim(req.ContainerId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create container: %w", err)
+		return nil, err
 	}
+	defer shim.Close()
+
 	startReq := shimTask.StartRequest{
 		ContainerId: req.ContainerId,
+		ExecId:      req.ExecId,
+	}
+	resp, err := shim.Start(ctx, &startReq)
+	if err != nil {
+		if req.ExecId != "" {
+			return nil, fmt.Errorf("failed to start exec %s: %w", req.ExecId, err)
+		}
+		return nil, fmt.Errorf("failed to start container: %w", err)
+	}
+	if req.ExecId != "" {
+		// exec processes come and go without changing the container's status
+		return &containerTask.StartResponse{Pid: resp.Pid}, nil
+	}
+	if _, err := s.setStatus(req.ContainerId, statusRunning); err != nil {
+		return nil, err
+	}
+	return &containerTask.StartResponse{Pid: resp.Pid}, nil
+}
+
+func (s *ContainerTaskServiceImpl) State(ctx context.Context, req *containerTask.StateRequest) (*containerTask.StateResponse, error) {
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	resp, err := shim.State(ctx, &shimTask.StateRequest{Id: req.ContainerId, ExecId: req.ExecId})
+	if err != nil {
+		return nil, err
+	}
+	if status, ok := containerStatus[taskState(resp.Status)]; ok && req.ExecId == "" {
+		// the shim is the authority, catch up with what happened behind our back
+		if _, err := s.store.Update(req.ContainerId, func(c *containerTask.Container) error {
+			if c.Status != status {
+				c.Status = status
+				c.UpdatedAt = timestamppb.Now()
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
 	}
-	TaskServiceImpl.Start(TaskServiceImpl{}, ctx, &startReq)
-	return &containerTask.StartResponse{Pid: pid}, nil
+	return &containerTask.StateResponse{
+		ContainerId:  resp.Id,
+		ExecId:       resp.ExecId,
+		Bundle:       resp.Bundle,
+		Pid:          resp.Pid,
+		Status:       resp.Status,
+		Stdin:        resp.Stdin,
+		Stdout:       resp.Stdout,
+		Stderr:       resp.Stderr,
+		Terminal:     resp.Terminal,
+		ExitStatus:   resp.ExitStatus,
+		ExitedAt:     resp.ExitedAt,
+		StartedAt:    resp.StartedAt,
+		RestartCount: resp.RestartCount,
+		ExitReason:   resp.ExitReason,
+		OomKilled:    resp.OomKilled,
+		MemoryLimit:  resp.MemoryLimit,
+	}, nil
 }
-func createContainer(bundlePath, containerID string) error {
-	createBundle(bundlePath)
-	cmdCreate := exec.Command("runc", "create", "--bundle", bundlePath, containerID)
-	cmdCreate.Stdout = os.Stdout
-	cmdCreate.Stderr = os.Stderr
-	if err := cmdCreate.Run(); err != nil {
-		return fmt.Errorf("failed to create container: %w", err)
+
+func (s *ContainerTaskServiceImpl) Stats(ctx context.Context, req *containerTask.StatsRequest) (*containerTask.StatsResponse, error) {
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
 	}
-	fmt.Println("Container created:", containerID)
-	return nil
+	defer shim.Close()
+
+	resp, err := shim.Stats(ctx, &shimTask.StatsRequest{Id: req.ContainerId})
+	if err != nil {
+		return nil, err
+	}
+	return toStats(resp), nil
+}
+
+// toStats copies the stats a shim reports into the daemon's API types
+func toStats(resp *shimTask.StatsResponse) *containerTask.StatsResponse {
+	stats := &containerTask.StatsResponse{
+		Id:     resp.Id,
+		ReadAt: resp.ReadAt,
+	}
+	if c := resp.Cpu; c != nil {
+		stats.Cpu = &containerTask.CPUStats{
+			UsageUsec:     c.UsageUsec,
+			UserUsec:      c.UserUsec,
+			SystemUsec:    c.SystemUsec,
+			NrPeriods:     c.NrPeriods,
+			NrThrottled:   c.NrThrottled,
+			ThrottledUsec: c.ThrottledUsec,
+		}
+	}
+	if m := resp.Memory; m != nil {
+		stats.Memory = &containerTask.MemoryStats{
+			Usage:         m.Usage,
+			Limit:         m.Limit,
+			Peak:          m.Peak,
+			SwapUsage:     m.SwapUsage,
+			OomEvents:     m.OomEvents,
+			OomKillEvents: m.OomKillEvents,
+		}
+	}
+	if p := resp.Pids; p != nil {
+		stats.Pids = &containerTask.PidsStats{Current: p.Current, Limit: p.Limit}
+	}
+	if io := resp.Io; io != nil {
+		stats.Io = &containerTask.IOStats{
+			ReadBytes:  io.ReadBytes,
+			WriteBytes: io.WriteBytes,
+			ReadOps:    io.ReadOps,
+			WriteOps:   io.WriteOps,
+		}
+	}
+	for _, n := range resp.Networks {
+		stats.Networks = append(stats.Networks, &containerTask.NetworkStats{
+			Name:      n.Name,
+			RxBytes:   n.RxBytes,
+			RxPackets: n.RxPackets,
+			RxErrors:  n.RxErrors,
+			RxDropped: n.RxDropped,
+			TxBytes:   n.TxBytes,
+			TxPackets: n.TxPackets,
+			TxErrors:  n.TxErrors,
+			TxDropped: n.TxDropped,
+		})
+	}
+	return stats
 }
 
-func createBundle(bundlePath string) error {
+func (s *ContainerTaskServiceImpl) Pause(ctx context.Context, req *containerTask.PauseRequest) (*emptypb.Empty, error) {
+	fmt.Println("function pause called on grpc")
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	if _, err := shim.Pause(ctx, &shimTask.PauseRequest{Id: req.ContainerId}); err != nil {
+		return nil, fmt.Errorf("failed to pause container: %w", err)
+	}
+	if _, err := s.setStatus(req.ContainerId, statusPaused); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ContainerTaskServiceImpl) Resume(ctx context.Context, req *containerTask.ResumeRequest) (*emptypb.Empty, error) {
+	fmt.Println("function resume called on grpc")
+	shim, err := s.connectContainerShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	if _, err := shim.Resume(ctx, &shimTask.ResumeRequest{Id: req.ContainerId}); err != nil {
+		return nil, fmt.Errorf("failed to resume container: %w", err)
+	}
+	if _, err := s.setStatus(req.ContainerId, statusRunning); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ContainerTaskServiceImpl) RestartStatus(ctx context.Context, req *containerTask.RestartStatusRequest) (*containerTask.RestartStatusResponse, error) {
+	shim, err := connectShim(req.ContainerId)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+
+	resp, err := shim.RestartStatus(ctx, &shimTask.RestartStatusRequest{Id: req.ContainerId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restart status: %w", err)
+	}
+	return &containerTask.RestartStatusResponse{
+		ContainerId:    req.ContainerId,
+		Pid:            resp.Pid,
+		RestartCount:   resp.RestartCount,
+		LastExitStatus: resp.LastExitStatus,
+		LastExitAt:     resp.LastExitAt,
+		Backoff:        resp.Backoff,
+		NextRestartAt:  resp.NextRestartAt,
+		Reason:         resp.Reason,
+		OomKills:       resp.OomKills,
+		LastExitReason: resp.LastExitReason,
+	}, nil
+}
+
+func (s *ContainerTaskServiceImpl) setStatus(id, status string) (*containerTask.Container, error) {
+	return s.store.Update(id, func(c *containerTask.Container) error {
+		c.Status = status
+		c.UpdatedAt = timestamppb.Now()
+		return nil
+	})
+}
+
+func toShimRestartPolicy(p *containerTask.RestartPolicy) *shimTask.RestartPolicy {
+	if p == nil {
+		return nil
+	}
+	return &shimTask.RestartPolicy{
+		Name:         p.Name,
+		MaxBackoff:   p.MaxBackoff,
+		StableWindow: p.StableWindow,
+	}
+}
+
+// createBundle prepares the bundle directory. The spec the container came with
+// replaces config.json, without one a config.json already in the bundle is
+// kept and runc generates the default otherwise.
+func createBundle(bundlePath string, spec *specs.Spec) error {
 	if err := os.MkdirAll(bundlePath, 0755); err != nil {
 		return fmt.Errorf("failed to create bundle directory: %w", err)
 	}
-	cmd := exec.Command("runc", "spec")
-	cmd.Dir = bundlePath // Set working directory to bundle path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to generate default spec: %w", err)
+	configPath := filepath.Join(bundlePath, "config.json")
+	if spec != nil {
+		if err := writeBundleSpec(bundlePath, spec); err != nil {
+			return err
+		}
+	} else if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		cmd := exec.Command("runc", "spec")
+		cmd.Dir = bundlePath // Set working directory to bundle path
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to generate default spec: %w", err)
+		}
+		fmt.Println("Default runc spec created at:", configPath)
 	}
-	if err := os.MkdirAll(bundlePath+"/rootfs", 0755); err != nil {
+	rootfs, err := bundleRootfs(bundlePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(rootfs, 0755); err != nil {
 		return fmt.Errorf("failed to create bundle rootfs directory: %w", err)
 	}
-	fmt.Println("Default runc spec created at:", bundlePath+"/config.json")
-
 	return nil
 }
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	containerTask "kettle/api/kettle"
+	task "kettle/api/shim"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/ttrpc"
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// newUpdateTest sets up a service with a container whose bundle limits its
+// memory to 64MiB
+func newUpdateTest(t *testing.T, container *containerTask.Container) *ContainerTaskServiceImpl {
+	t.Helper()
+	store, err := newContainerStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.Bundle = t.TempDir()
+	spec := oci.DefaultSpec()
+	if err := oci.WithMemoryLimit(64 << 20)(spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBundleSpec(container.Bundle, spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(container); err != nil {
+		t.Fatal(err)
+	}
+	return &ContainerTaskServiceImpl{store: store}
+}
+
+// serveShim serves svc as the shim of container id
+func serveShim(t *testing.T, id string, svc *TaskServiceImpl) {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("shim sockets live under /run")
+	}
+	path := shimSocketPath(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0711); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ttrpc.NewServer(ttrpc.WithUnaryServerInterceptor(shimErrorInterceptor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.RegisterTaskService(server, svc)
+	go server.Serve(context.Background(), listener)
+	t.Cleanup(func() {
+		server.Close()
+		os.RemoveAll(filepath.Dir(path))
+	})
+}
+
+// bundleMemoryLimit is the memory limit in the bundle's spec
+func bundleMemoryLimit(t *testing.T, bundle string) int64 {
+	t.Helper()
+	spec, err := readBundleSpec(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *spec.Linux.Resources.Memory.Limit
+}
+
+func TestUpdateResources(t *testing.T) {
+	limit := int64(128 << 20)
+	resources, err := typeurl.MarshalAnyToProto(&specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		paths []string
+		// spec is the record's, nil for a container created from a bundle
+		spec *anypb.Any
+		want int64
+		err  func(error) bool
+	}{
+		{"applied", []string{"resources"}, nil, limit, nil},
+		// nothing is applied for a mask Update refuses
+		{"unknown path", []string{"resources", "bogus"}, nil, 64 << 20, errdefs.IsInvalidArgument},
+		// nor is anything kept when the record cannot take the update
+		{"record update fails", []string{"resources"}, &anypb.Any{TypeUrl: "bogus"}, 64 << 20, errdefs.IsInvalidArgument},
+	}
+	for _, tt := range tests {
+		// exited containers have their limits in the bundle only
+		container := &containerTask.Container{ID: "c1", Status: statusExited, Spec: tt.spec}
+		s := newUpdateTest(t, container)
+		_, err := s.Update(context.Background(), &containerTask.UpdateContainerRequest{
+			Container:  &containerTask.Container{ID: container.ID},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.paths},
+			Resources:  resources,
+		})
+		if tt.err == nil && err != nil || tt.err != nil && !tt.err(err) {
+			t.Errorf("%s: Update = %v", tt.name, err)
+		}
+		if got := bundleMemoryLimit(t, container.Bundle); got != tt.want {
+			t.Errorf("%s: bundle memory limit = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckUpdatePaths(t *testing.T) {
+	if err := checkUpdatePaths([]string{"labels", "labels.app", "restart_policy", "resources"}); err != nil {
+		t.Errorf("checkUpdatePaths: %v", err)
+	}
+	for _, paths := range [][]string{{"spec"}, {"labels", "status"}, {"labelsx"}} {
+		if err := checkUpdatePaths(paths); !errdefs.IsInvalidArgument(err) {
+			t.Errorf("checkUpdatePaths(%v) = %v, want invalid argument", paths, err)
+		}
+	}
+}
+
+func TestUpdateRestartPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		// shim is the container's shim, nil for one that is gone
+		shim func() *TaskServiceImpl
+		// breakStore has the record fail to be written
+		breakStore bool
+		want       string
+	}{
+		{"applied", runningShim, false, RestartAlways},
+		{"no shim", nil, false, RestartOnFailure},
+		{"refused by the shim", newTaskService, false, RestartOnFailure},
+		{"record not written", runningShim, true, RestartOnFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := &containerTask.Container{
+				ID:            fmt.Sprintf("update-%d", os.Getpid()),
+				Status:        statusRunning,
+				RestartPolicy: &containerTask.RestartPolicy{Name: RestartOnFailure},
+			}
+			s := newUpdateTest(t, container)
+			var shim *TaskServiceImpl
+			if tt.shim != nil {
+				shim = tt.shim()
+				serveShim(t, container.ID, shim)
+			}
+			if tt.breakStore {
+				if err := os.RemoveAll(s.store.root); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(s.store.root, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := s.Update(context.Background(), &containerTask.UpdateContainerRequest{
+				Container:  &containerTask.Container{ID: container.ID, RestartPolicy: &containerTask.RestartPolicy{Name: RestartAlways}},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"restart_policy"}},
+			})
+			if (err == nil) != (tt.want == RestartAlways) {
+				t.Errorf("Update = %v", err)
+			}
+			stored, err := s.store.Get(container.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.RestartPolicy.GetName() != tt.want {
+				t.Errorf("stored policy = %s, want %s", stored.RestartPolicy.GetName(), tt.want)
+			}
+			// a shim that took the new policy is given the old one back
+			if shim != nil && shim.state != stateUnknown && shim.policy.name != tt.want {
+				t.Errorf("shim policy = %s, want %s", shim.policy.name, tt.want)
+			}
+		})
+	}
+}
+
+// runningShim is the shim of a running container
+func runningShim() *TaskServiceImpl {
+	svc := newTaskService()
+	svc.state = stateRunning
+	return svc
+}
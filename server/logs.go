@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	containerTask "kettle/api/kettle"
+	shimTask "kettle/api/shim"
+	"kettle/pkg/logs"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// logPath is the container log the shim writes, kept next to the container's record
+func (s *ContainerTaskServiceImpl) logPath(id string) string {
+	return filepath.Join(s.store.containerDir(id), "container.log")
+}
+
+func (s *ContainerTaskServiceImpl) Logs(req *containerTask.LogsRequest, stream containerTask.Containers_LogsServer) error {
+	if _, err := s.store.Get(req.Id); err != nil {
+		return err
+	}
+	opts := logs.ReadOptions{Tail: -1}
+	if req.Tail != nil {
+		opts.Tail = int(*req.Tail)
+	}
+	if req.Since != nil {
+		opts.Since = req.Since.AsTime()
+	}
+
+	path := s.logPath(req.Id)
+	entries, offset, err := logs.Read(path, s.config.LogMaxFiles, opts)
+	if err != nil {
+		return err
+	}
+	send := func(e logs.Entry) error {
+		return stream.Send(&containerTask.LogEntry{
+			Timestamp: timestamppb.New(e.Timestamp),
+			Stream:    e.Stream,
+			Line:      e.Line,
+			Partial:   e.Partial,
+		})
+	}
+	for _, e := range entries {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+	if !req.Follow {
+		return nil
+	}
+	// nothing may have been logged yet, make sure there is a file to follow
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	// following ends with the container, once its last output was sent
+	return logs.Follow(stream.Context(), path, offset, taskExited(stream.Context(), req.Id), send)
+}
+
+// taskExited returns a channel closed once the init process of the container
+// is down for good, right away when its shim is gone
+func taskExited(ctx context.Context, id string) <-chan struct{} {
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		shim, err := connectShim(id)
+		if err != nil {
+			return
+		}
+		defer shim.Close()
+		shim.Wait(ctx, &shimTask.WaitRequest{Id: id})
+	}()
+	return exited
+}
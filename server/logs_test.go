@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/logs"
+
+	"google.golang.org/grpc"
+)
+
+// logStream collects the lines a Logs call sends
+type logStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	mu    sync.Mutex
+	lines []string
+}
+
+func (s *logStream) Context() context.Context { return s.ctx }
+
+func (s *logStream) Send(e *containerTask.LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines = append(s.lines, string(e.Line))
+	return nil
+}
+
+func (s *logStream) sent() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.lines, ",")
+}
+
+// newLogsTest sets up a service with a container that logged "before"
+func newLogsTest(t *testing.T, id string) (*ContainerTaskServiceImpl, *logs.Writer) {
+	t.Helper()
+	store, err := newContainerStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(&containerTask.Container{ID: id}); err != nil {
+		t.Fatal(err)
+	}
+	s := &ContainerTaskServiceImpl{store: store, config: Config{LogMaxFiles: 1}}
+	w, err := logs.NewWriter(s.logPath(id), 1<<20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+	if err := w.Copy(logs.Stdout, strings.NewReader("before\n")); err != nil {
+		t.Fatal(err)
+	}
+	return s, w
+}
+
+// followLogs runs a following Logs call, the returned channel gets its result
+func followLogs(s *ContainerTaskServiceImpl, id string, stream *logStream) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Logs(&containerTask.LogsRequest{Id: id, Follow: true}, stream)
+	}()
+	return done
+}
+
+func TestLogsFollowEndsWithTask(t *testing.T) {
+	id := fmt.Sprintf("logs-%d", os.Getpid())
+	s, w := newLogsTest(t, id)
+	svc := runningShim()
+	serveShim(t, id, svc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream := &logStream{ctx: ctx}
+	done := followLogs(s, id, stream)
+	if err := w.Copy(logs.Stdout, strings.NewReader("after\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("Logs returned while the container runs: %v", err)
+	case <-time.After(time.Second):
+	}
+
+	svc.mu.Lock()
+	svc.markStopped()
+	svc.mu.Unlock()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Logs: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Logs kept following after the container exited")
+	}
+	if got := stream.sent(); got != "before,after" {
+		t.Errorf("sent %s, want before,after", got)
+	}
+}
+
+func TestLogsFollowWithoutShim(t *testing.T) {
+	// the container is long gone along with its shim
+	id := fmt.Sprintf("logs-gone-%d", os.Getpid())
+	s, _ := newLogsTest(t, id)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream := &logStream{ctx: ctx}
+	select {
+	case err := <-followLogs(s, id, stream):
+		if err != nil {
+			t.Fatalf("Logs: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Logs kept following a container without a shim")
+	}
+	if got := stream.sent(); got != "before" {
+		t.Errorf("sent %s, want before", got)
+	}
+}
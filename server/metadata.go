@@ -0,0 +1,201 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	containerTask "kettle/api/kettle"
+
+	"github.com/containerd/errdefs"
+	"google.golang.org/protobuf/proto"
+)
+
+// metadataFile is the name of the record kept in each container's directory
+const metadataFile = "container.pb"
+
+// containerStore keeps the daemon's record of every container it created.
+// Records live in <root>/containers/<id>/container.pb and are replaced by
+// renaming a fully written temporary file over them, so a crash leaves either
+// the old or the new record behind but never a torn one.
+type containerStore struct {
+	mu         sync.Mutex
+	root       string
+	containers map[string]*containerTask.Container
+}
+
+// newContainerStore opens the store under root and loads every record in it
+func newContainerStore(root string) (*containerStore, error) {
+	s := &containerStore{
+		root:       filepath.Join(root, "containers"),
+		containers: make(map[string]*containerTask.Container),
+	}
+	if err := os.MkdirAll(s.root, 0711); err != nil {
+		return nil, fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		c, err := readContainer(filepath.Join(s.root, entry.Name(), metadataFile))
+		if os.IsNotExist(err) {
+			// removed halfway through a delete
+			continue
+		}
+		if err != nil {
+			fmt.Println("skipping unreadable container record:", entry.Name(), err)
+			continue
+		}
+		s.containers[c.ID] = c
+	}
+	return s, nil
+}
+
+func readContainer(path string) (*containerTask.Container, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c containerTask.Container
+	if err := proto.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// containerDir is where everything the daemon keeps for a container lives
+func (s *containerStore) containerDir(id string) string {
+	return filepath.Join(s.root, id)
+}
+
+// write atomically replaces the on-disk record of c
+func (s *containerStore) write(c *containerTask.Container) error {
+	data, err := proto.Marshal(c)
+	if err != nil {
+		return err
+	}
+	dir := s.containerDir(c.ID)
+	if err := os.MkdirAll(dir, 0711); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".container-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, metadataFile)); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+func syncDir(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *containerStore) Get(id string) (*containerTask.Container, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.containers[id]
+	if !ok {
+		return nil, fmt.Errorf("container %q: %w", id, errdefs.ErrNotFound)
+	}
+	return proto.Clone(c).(*containerTask.Container), nil
+}
+
+// List returns the containers carrying all of the given labels, sorted by ID
+func (s *containerStore) List(labels map[string]string) []*containerTask.Container {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*containerTask.Container
+	for _, c := range s.containers {
+		if matchLabels(c.Labels, labels) {
+			list = append(list, proto.Clone(c).(*containerTask.Container))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *containerStore) Create(c *containerTask.Container) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.containers[c.ID]; ok {
+		return fmt.Errorf("container %q: %w", c.ID, errdefs.ErrAlreadyExists)
+	}
+	if err := s.write(c); err != nil {
+		return fmt.Errorf("failed to persist container %q: %w", c.ID, err)
+	}
+	s.containers[c.ID] = proto.Clone(c).(*containerTask.Container)
+	return nil
+}
+
+// Update runs fn against a copy of the stored container and keeps the result
+// once it has been written to disk
+func (s *containerStore) Update(id string, fn func(*containerTask.Container) error) (*containerTask.Container, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.containers[id]
+	if !ok {
+		return nil, fmt.Errorf("container %q: %w", id, errdefs.ErrNotFound)
+	}
+	updated := proto.Clone(c).(*containerTask.Container)
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	if err := s.write(updated); err != nil {
+		return nil, fmt.Errorf("failed to persist container %q: %w", id, err)
+	}
+	s.containers[id] = updated
+	return proto.Clone(updated).(*containerTask.Container), nil
+}
+
+func (s *containerStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.containers[id]; !ok {
+		return fmt.Errorf("container %q: %w", id, errdefs.ErrNotFound)
+	}
+	// dropping the record first means a crash below leaves only an
+	// orphaned directory, which the loader ignores
+	dir := s.containerDir(id)
+	if err := os.Remove(filepath.Join(dir, metadataFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove container %q: %w", id, err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove container %q: %w", id, err)
+	}
+	delete(s.containers, id)
+	return nil
+}
+
+func matchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	containerTask "kettle/api/kettle"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestContainerStoreReload(t *testing.T) {
+	root := t.TempDir()
+	store, err := newContainerStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []*containerTask.Container{
+		{ID: "b", Status: statusCreated, Labels: map[string]string{"app": "web"}},
+		{ID: "a", Status: statusCreated, Labels: map[string]string{"app": "web", "tier": "front"}},
+		{ID: "c", Status: statusCreated},
+	} {
+		if err := store.Create(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Create(&containerTask.Container{ID: "a"}); !errdefs.IsAlreadyExists(err) {
+		t.Errorf("second Create = %v, want already exists", err)
+	}
+	if _, err := store.Update("a", func(c *containerTask.Container) error {
+		c.Status = statusRunning
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("c"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := newContainerStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := reopened.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Status != statusRunning || a.Labels["tier"] != "front" {
+		t.Errorf("reloaded a = %+v", a)
+	}
+	if _, err := reopened.Get("c"); !errdefs.IsNotFound(err) {
+		t.Errorf("Get of a deleted container = %v, want not found", err)
+	}
+	if _, err := os.Stat(reopened.containerDir("c")); !os.IsNotExist(err) {
+		t.Errorf("directory of a deleted container left: %v", err)
+	}
+
+	tests := []struct {
+		labels map[string]string
+		want   []string
+	}{
+		{nil, []string{"a", "b"}},
+		{map[string]string{"app": "web"}, []string{"a", "b"}},
+		{map[string]string{"tier": "front"}, []string{"a"}},
+		{map[string]string{"app": "db"}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range reopened.List(tt.labels) {
+			got = append(got, c.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("List(%v) = %v, want %v", tt.labels, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("List(%v) = %v, want %v", tt.labels, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestContainerStoreSkipsBadRecords(t *testing.T) {
+	root := t.TempDir()
+	store, err := newContainerStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(&containerTask.Container{ID: "good", Status: statusCreated}); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		// a temporary file left by a crash before its rename
+		"good/.container-123":     "torn",
+		"corrupt/" + metadataFile: "\xff\xff\xff",
+		// a directory whose record was removed halfway through a delete
+		"deleted/container.log": "",
+		"stray":                 "",
+	}
+	for name, data := range files {
+		path := filepath.Join(store.root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0711); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reopened, err := newContainerStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := reopened.List(nil)
+	if len(list) != 1 || list[0].ID != "good" || list[0].Status != statusCreated {
+		t.Errorf("loaded %v, want only good", list)
+	}
+}
+
+func TestContainerStoreWrite(t *testing.T) {
+	store, err := newContainerStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(&containerTask.Container{ID: "c", Status: statusCreated}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := store.Update("c", func(c *containerTask.Container) error {
+			c.Labels = map[string]string{"n": strconv.Itoa(i)}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// every write renames its temporary file over the record
+	entries, err := os.ReadDir(store.containerDir("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != metadataFile {
+		t.Errorf("container directory holds %v, want only %s", entries, metadataFile)
+	}
+	onDisk, err := readContainer(filepath.Join(store.containerDir("c"), metadataFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.Labels["n"] != "2" {
+		t.Errorf("record on disk = %+v, want the last update", onDisk)
+	}
+
+	// copies are handed out, changing them changes nothing stored
+	c, _ := store.Get("c")
+	c.Status = statusRunning
+	if c, _ := store.Get("c"); c.Status != statusCreated {
+		t.Errorf("status = %s after changing a copy", c.Status)
+	}
+
+	// an update fn that fails keeps the record as it was
+	refused := errors.New("refused")
+	if _, err := store.Update("c", func(c *containerTask.Container) error {
+		c.Status = statusRunning
+		return refused
+	}); !errors.Is(err, refused) {
+		t.Errorf("Update = %v, want the error of fn", err)
+	}
+	if _, err := store.Update("missing", func(*containerTask.Container) error { return nil }); !errdefs.IsNotFound(err) {
+		t.Errorf("Update of a missing container = %v, want not found", err)
+	}
+
+	// so does one that cannot be written
+	dir := store.containerDir("c")
+	if err := os.Rename(dir, dir+".moved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Update("c", func(c *containerTask.Container) error {
+		c.Status = statusRunning
+		return nil
+	}); err == nil {
+		t.Error("Update succeeded without a directory to write to")
+	}
+	if c, _ := store.Get("c"); c.Status != statusCreated {
+		t.Errorf("status = %s after a failed write, want %s", c.Status, statusCreated)
+	}
+	// and a container that could not be written is not created
+	if err := os.WriteFile(store.containerDir("d"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(&containerTask.Container{ID: "d"}); err == nil {
+		t.Error("Create succeeded without a directory to write to")
+	}
+	if _, err := store.Get("d"); !errdefs.IsNotFound(err) {
+		t.Errorf("Get of a container that failed to be created = %v, want not found", err)
+	}
+}
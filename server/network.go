@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/netns"
+
+	cni "github.com/containerd/go-cni"
+	"github.com/containernetworking/cni/libcni"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// Where CNI network configs and plugins are looked for unless the daemon is
+// told otherwise
+const (
+	DefaultCNIConfDir = "/etc/cni/net.d"
+	DefaultCNIBinDir  = "/opt/cni/bin"
+)
+
+// cniConfDir is the conf directory config asks for
+func cniConfDir(config Config) string {
+	if config.CNIConfDir == "" {
+		return DefaultCNIConfDir
+	}
+	return config.CNIConfDir
+}
+
+// newNetwork sets up CNI with the plugin directories of config. The network
+// configs are loaded whenever a container is created, so that networks can be
+// added to the conf directory while the daemon runs.
+func newNetwork(config Config) (cni.CNI, error) {
+	binDir := config.CNIBinDir
+	if binDir == "" {
+		binDir = DefaultCNIBinDir
+	}
+	network, err := cni.New(cni.WithPluginConfDir(cniConfDir(config)), cni.WithPluginDir([]string{binDir}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cni: %w", err)
+	}
+	return network, nil
+}
+
+// loadNetwork reads the first network config of the conf directory. It
+// returns false without one, in which case containers keep the namespace runc
+// gives them.
+func (s *ContainerTaskServiceImpl) loadNetwork() (bool, error) {
+	files, err := libcni.ConfFiles(cniConfDir(s.config), []string{".conf", ".conflist", ".json"})
+	if err != nil {
+		return false, fmt.Errorf("failed to read cni config directory: %w", err)
+	}
+	if len(files) == 0 {
+		return false, nil
+	}
+	if err := s.network.Load(cni.WithDefaultConf); err != nil {
+		return false, fmt.Errorf("failed to load cni config: %w", err)
+	}
+	return true, nil
+}
+
+// netnsName is the name of the container's network namespace in netns.DefaultDir
+func netnsName(id string) string {
+	return "kettle-" + id
+}
+
+// setupNetwork creates a named network namespace for a container that asks
+// for a namespace of its own, adds it to the CNI networks and points the
+// bundle's spec at it. container.Network records the result.
+func (s *ContainerTaskServiceImpl) setupNetwork(ctx context.Context, container *containerTask.Container) error {
+	spec, err := readBundleSpec(container.Bundle)
+	if err != nil {
+		return err
+	}
+	ns := networkNamespace(spec)
+	if ns == nil || ns.Path != "" {
+		// sharing the host's or another namespace, nothing to set up
+		return nil
+	}
+	ok, err := s.loadNetwork()
+	if err != nil || !ok {
+		return err
+	}
+
+	path, err := netns.Create(netns.DefaultDir, netnsName(container.ID))
+	if err != nil {
+		return err
+	}
+	result, err := s.network.SetupSerially(ctx, container.ID, path)
+	if err != nil {
+		// the plugins that did their part are told to undo it
+		s.network.Remove(ctx, container.ID, path)
+		netns.Remove(path)
+		return fmt.Errorf("failed to set up network: %w", err)
+	}
+	container.Network = toContainerNetwork(path, result)
+	ns.Path = path
+	if err := writeBundleSpec(container.Bundle, spec); err != nil {
+		s.removeNetwork(ctx, container)
+		return err
+	}
+	fmt.Println("Network set up for", container.ID, "in", path)
+	return nil
+}
+
+// removeNetwork takes the container out of the CNI networks and removes its
+// network namespace
+func (s *ContainerTaskServiceImpl) removeNetwork(ctx context.Context, container *containerTask.Container) error {
+	if container.Network == nil {
+		return nil
+	}
+	if ok, err := s.loadNetwork(); err != nil {
+		return err
+	} else if ok {
+		// a namespace that is gone already still has its addresses released
+		path := container.Network.Netns
+		if !netns.Exists(path) {
+			path = ""
+		}
+		if err := s.network.Remove(ctx, container.ID, path); err != nil {
+			return fmt.Errorf("failed to tear down network: %w", err)
+		}
+	}
+	return netns.Remove(container.Network.Netns)
+}
+
+// checkNetwork has the CNI plugins verify the network of the container is
+// still the way they set it up
+func (s *ContainerTaskServiceImpl) checkNetwork(ctx context.Context, container *containerTask.Container) error {
+	if container.Network == nil {
+		return nil
+	}
+	if !netns.Exists(container.Network.Netns) {
+		return fmt.Errorf("network namespace %s is gone", container.Network.Netns)
+	}
+	if ok, err := s.loadNetwork(); err != nil || !ok {
+		return err
+	}
+	return s.network.Check(ctx, container.ID, container.Network.Netns)
+}
+
+// networkNamespace returns the network namespace entry of the spec, nil when
+// the container stays in the host's namespace
+func networkNamespace(spec *specs.Spec) *specs.LinuxNamespace {
+	if spec.Linux == nil {
+		return nil
+	}
+	for i := range spec.Linux.Namespaces {
+		if spec.Linux.Namespaces[i].Type == specs.NetworkNamespace {
+			return &spec.Linux.Namespaces[i]
+		}
+	}
+	return nil
+}
+
+func toContainerNetwork(path string, result *cni.Result) *containerTask.ContainerNetwork {
+	network := &containerTask.ContainerNetwork{Netns: path}
+	names := make([]string, 0, len(result.Interfaces))
+	for name, iface := range result.Interfaces {
+		// interfaces without a sandbox are the host ends of the plugins
+		if iface.Sandbox == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		iface := result.Interfaces[name]
+		ni := &containerTask.NetworkInterface{Name: name, Mac: iface.Mac}
+		for _, ip := range iface.IPConfigs {
+			ni.Ips = append(ni.Ips, ip.IP.String())
+		}
+		network.Interfaces = append(network.Interfaces, ni)
+	}
+	return network
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/netns"
+	"kettle/pkg/oci"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// stubPlugin is a CNI plugin that records its calls to a file and hands out a
+// fixed address. Containers with "fail" in their ID fail to be added.
+const stubPlugin = `#!/bin/sh
+echo "$CNI_COMMAND $CNI_CONTAINERID $CNI_NETNS" >> %s
+case "$CNI_COMMAND" in
+VERSION)
+	echo '{"cniVersion":"1.0.0","supportedVersions":["0.4.0","1.0.0"]}';;
+ADD)
+	case "$CNI_CONTAINERID" in *fail*)
+		echo '{"cniVersion":"1.0.0","code":100,"msg":"no addresses left"}'
+		exit 1;;
+	esac
+	echo "{\"cniVersion\":\"1.0.0\",\"interfaces\":[{\"name\":\"veth0\",\"mac\":\"aa:bb:cc:dd:ee:00\"},{\"name\":\"$CNI_IFNAME\",\"mac\":\"aa:bb:cc:dd:ee:ff\",\"sandbox\":\"$CNI_NETNS\"}],\"ips\":[{\"address\":\"10.88.0.5/16\",\"interface\":1}]}";;
+esac
+`
+
+// newNetworkTest sets up a service using the stub plugin, with its network
+// config only if withConf is set. It returns the file the plugin records its
+// calls in.
+func newNetworkTest(t *testing.T, withConf bool) (*ContainerTaskServiceImpl, string) {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("network namespaces need root")
+	}
+	dir := t.TempDir()
+	config := Config{
+		CNIConfDir: filepath.Join(dir, "conf"),
+		CNIBinDir:  filepath.Join(dir, "bin"),
+	}
+	calls := filepath.Join(dir, "calls")
+	for _, d := range []string{config.CNIConfDir, config.CNIBinDir} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(config.CNIBinDir, "stub"), []byte(fmt.Sprintf(stubPlugin, calls)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if withConf {
+		conf := `{"cniVersion":"1.0.0","name":"test","plugins":[{"type":"stub"}]}`
+		if err := os.WriteFile(filepath.Join(config.CNIConfDir, "10-test.conflist"), []byte(conf), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	network, err := newNetwork(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ContainerTaskServiceImpl{config: config, network: network}, calls
+}
+
+// newNetworkContainer returns a container whose bundle holds spec
+func newNetworkContainer(t *testing.T, id string, spec *specs.Spec) *containerTask.Container {
+	t.Helper()
+	container := &containerTask.Container{ID: fmt.Sprintf("%s-%d", id, os.Getpid()), Bundle: t.TempDir()}
+	if err := writeBundleSpec(container.Bundle, spec); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { netns.Remove(filepath.Join(netns.DefaultDir, netnsName(container.ID))) })
+	return container
+}
+
+// readCalls returns the commands the stub plugin ran
+func readCalls(t *testing.T, calls string) []string {
+	t.Helper()
+	data, err := os.ReadFile(calls)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	var commands []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if cmd, _, _ := strings.Cut(line, " "); cmd != "" && cmd != "VERSION" {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+func TestNetworkLifecycle(t *testing.T) {
+	s, calls := newNetworkTest(t, true)
+	ctx := context.Background()
+	container := newNetworkContainer(t, "net", oci.DefaultSpec())
+
+	if err := s.setupNetwork(ctx, container); err != nil {
+		t.Fatalf("setupNetwork: %v", err)
+	}
+	network := container.Network
+	if network == nil {
+		t.Fatal("no network recorded")
+	}
+	if want := filepath.Join(netns.DefaultDir, netnsName(container.ID)); network.Netns != want {
+		t.Errorf("netns = %s, want %s", network.Netns, want)
+	}
+	if !netns.Exists(network.Netns) {
+		t.Error("network namespace was not created")
+	}
+	// the host end of the plugin is left out
+	if len(network.Interfaces) != 1 || network.Interfaces[0].Name != "eth0" {
+		t.Fatalf("interfaces = %v, want eth0 only", network.Interfaces)
+	}
+	if ips := network.Interfaces[0].Ips; len(ips) != 1 || ips[0] != "10.88.0.5" {
+		t.Errorf("eth0 ips = %v", ips)
+	}
+	spec, err := readBundleSpec(container.Bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns := networkNamespace(spec); ns == nil || ns.Path != network.Netns {
+		t.Errorf("bundle spec joins %v, want %s", ns, network.Netns)
+	}
+
+	if err := s.checkNetwork(ctx, container); err != nil {
+		t.Errorf("checkNetwork: %v", err)
+	}
+	if err := s.removeNetwork(ctx, container); err != nil {
+		t.Fatalf("removeNetwork: %v", err)
+	}
+	if netns.Exists(network.Netns) {
+		t.Error("network namespace outlived the network")
+	}
+	if _, err := os.Stat(network.Netns); !os.IsNotExist(err) {
+		t.Errorf("netns file left behind: %v", err)
+	}
+	if err := s.checkNetwork(ctx, container); err == nil {
+		t.Error("checkNetwork passed without a namespace")
+	}
+
+	if got := strings.Join(readCalls(t, calls), ","); got != "ADD,CHECK,DEL" {
+		t.Errorf("plugin calls = %s, want ADD,CHECK,DEL", got)
+	}
+}
+
+func TestNetworkSetupFailure(t *testing.T) {
+	s, calls := newNetworkTest(t, true)
+	container := newNetworkContainer(t, "fail", oci.DefaultSpec())
+
+	if err := s.setupNetwork(context.Background(), container); err == nil {
+		t.Fatal("setupNetwork succeeded with a failing plugin")
+	}
+	if container.Network != nil {
+		t.Errorf("network recorded for a failed setup: %v", container.Network)
+	}
+	path := filepath.Join(netns.DefaultDir, netnsName(container.ID))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("netns of the failed setup left behind: %v", err)
+	}
+	// the plugins are told to undo whatever they did
+	if got := strings.Join(readCalls(t, calls), ","); got != "ADD,DEL" {
+		t.Errorf("plugin calls = %s, want ADD,DEL", got)
+	}
+}
+
+func TestNetworkSkipped(t *testing.T) {
+	ctx := context.Background()
+
+	// without a network config containers keep the namespace runc gives them
+	s, _ := newNetworkTest(t, false)
+	container := newNetworkContainer(t, "noconf", oci.DefaultSpec())
+	if err := s.setupNetwork(ctx, container); err != nil || container.Network != nil {
+		t.Errorf("setupNetwork without config = %v, %v", container.Network, err)
+	}
+
+	// nor is anything set up for containers sharing the host's namespace
+	s, calls := newNetworkTest(t, true)
+	spec := oci.DefaultSpec()
+	spec.Linux.Namespaces = []specs.LinuxNamespace{{Type: specs.MountNamespace}}
+	container = newNetworkContainer(t, "host", spec)
+	if err := s.setupNetwork(ctx, container); err != nil || container.Network != nil {
+		t.Errorf("setupNetwork in the host namespace = %v, %v", container.Network, err)
+	}
+	if got := readCalls(t, calls); len(got) != 0 {
+		t.Errorf("plugin calls = %v, want none", got)
+	}
+	// removing a network that was never set up is fine
+	if err := s.removeNetwork(ctx, container); err != nil {
+		t.Errorf("removeNetwork: %v", err)
+	}
+}
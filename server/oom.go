@@ -0,0 +1,158 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/cgroups/v3"
+	"github.com/containerd/cgroups/v3/cgroup2"
+)
+
+// reasonOOMKilled tags an exit the OOM killer was involved in, same as docker
+const reasonOOMKilled = "OOMKilled"
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted, tests point it at a
+// directory of their own
+var cgroupRoot = "/sys/fs/cgroup"
+
+// oomPollInterval is how often memory.events is read while the container runs
+const oomPollInterval = time.Second
+
+// oomWatch follows the OOM kills in the cgroup of one run of the init process.
+// The kernel counts them in memory.events, an increase over the count the run
+// started with means the OOM killer took out one of its processes. The count
+// is polled while the container runs so that kills of processes other than
+// the init process are reported as they happen.
+type oomWatch struct {
+	// group is the cgroup below cgroupRoot
+	group    string
+	baseline uint64
+	onKill   func(kills, limit uint64)
+
+	mu       sync.Mutex
+	seen     uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// newOOMWatch starts watching the cgroup pid is in, calling onKill with the
+// number of new kills and the memory limit whenever the count goes up. It is
+// called once runc created the container, before the process runs anything.
+// It returns nil without cgroup v2.
+func newOOMWatch(pid int, onKill func(kills, limit uint64)) *oomWatch {
+	if cgroups.Mode() != cgroups.Unified {
+		return nil
+	}
+	group, err := cgroup2.PidGroupPath(pid)
+	if err != nil {
+		log.Printf("cannot watch for OOM kills of pid %d: %v", pid, err)
+		return nil
+	}
+	kills, err := readOOMKills(group)
+	if err != nil {
+		log.Printf("cannot watch for OOM kills of pid %d: %v", pid, err)
+		return nil
+	}
+	w := &oomWatch{
+		group:    group,
+		baseline: kills,
+		onKill:   onKill,
+		seen:     kills,
+		stop:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *oomWatch) run() {
+	ticker := time.NewTicker(oomPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.poll()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// poll reads the kill count and reports an increase since the last read
+func (w *oomWatch) poll() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	kills, err := readOOMKills(w.group)
+	if err != nil {
+		// the cgroup is gone with the container, nothing more to see
+		return w.seen
+	}
+	if kills > w.seen {
+		w.onKill(kills-w.seen, w.memoryLimit())
+		w.seen = kills
+	}
+	return w.seen
+}
+
+// memoryLimit is the memory limit of the cgroup, 0 without one
+func (w *oomWatch) memoryLimit() uint64 {
+	var limit uint64
+	if data, err := os.ReadFile(filepath.Join(cgroupRoot, w.group, "memory.max")); err == nil {
+		// "max" is no limit and fails to parse, leaving 0
+		limit, _ = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	}
+	return limit
+}
+
+// check stops the watch once the run exited and reports whether processes
+// were OOM-killed since it started, and the memory limit in effect, 0 without
+// one. Kills not reported yet are reported first. The cgroup outlives the exit
+// of its processes until runc deletes the container.
+func (w *oomWatch) check() (bool, uint64) {
+	if w == nil {
+		return false, 0
+	}
+	w.close()
+	if w.poll() <= w.baseline {
+		return false, 0
+	}
+	return true, w.memoryLimit()
+}
+
+// close stops polling, it is safe to call more than once and on a nil watch
+func (w *oomWatch) close() {
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// readOOMKills returns the oom_kill count of memory.events in group
+func readOOMKills(group string) (uint64, error) {
+	f, err := os.Open(filepath.Join(cgroupRoot, group, "memory.events"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if ok && key == "oom_kill" {
+			kills, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid oom_kill count %q: %w", value, err)
+			}
+			return kills, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
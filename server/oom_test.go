@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	task "kettle/api/shim"
+)
+
+// fakeCgroup points cgroupRoot at a new directory holding the cgroup group
+// with the given memory.events and memory.max
+func fakeCgroup(t *testing.T, group, events, max string) {
+	t.Helper()
+	root := t.TempDir()
+	old := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = old })
+	setCgroup(t, group, events, max)
+}
+
+// setCgroup replaces the memory.events and memory.max of group
+func setCgroup(t *testing.T, group, events, max string) {
+	t.Helper()
+	dir := filepath.Join(cgroupRoot, group)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "memory.events"), []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "memory.max"), []byte(max), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// memoryEvents is a memory.events file with the given oom_kill count
+func memoryEvents(kills string) string {
+	return "low 0\nhigh 0\nmax 12\noom 4\noom_kill " + kills + "\noom_group_kill 0\n"
+}
+
+func TestReadOOMKills(t *testing.T) {
+	tests := []struct {
+		events  string
+		want    uint64
+		invalid bool
+	}{
+		{memoryEvents("3"), 3, false},
+		{memoryEvents("0"), 0, false},
+		// kernels before 4.13 do not count kills
+		{"low 0\nhigh 0\nmax 0\noom 0\n", 0, false},
+		{memoryEvents("many"), 0, true},
+	}
+	for _, tt := range tests {
+		fakeCgroup(t, "c", tt.events, "max\n")
+		kills, err := readOOMKills("c")
+		if (err != nil) != tt.invalid || kills != tt.want {
+			t.Errorf("readOOMKills(%q) = %d, %v, want %d", tt.events, kills, err, tt.want)
+		}
+	}
+	if _, err := readOOMKills("missing"); err == nil {
+		t.Error("readOOMKills of a missing cgroup succeeded")
+	}
+}
+
+// kill is one call of an oomWatch's onKill
+type kill struct{ kills, limit uint64 }
+
+// newTestWatch watches group as newOOMWatch would, without polling on its own
+func newTestWatch(t *testing.T, group string, calls *[]kill) *oomWatch {
+	t.Helper()
+	kills, err := readOOMKills(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &oomWatch{
+		group:    group,
+		baseline: kills,
+		seen:     kills,
+		onKill:   func(kills, limit uint64) { *calls = append(*calls, kill{kills, limit}) },
+		stop:     make(chan struct{}),
+	}
+}
+
+func TestOOMWatch(t *testing.T) {
+	tests := []struct {
+		name string
+		// events are the oom_kill counts read by each poll
+		events []string
+		max    string
+		want   []kill
+		killed bool
+		limit  uint64
+	}{
+		{"no kills", []string{"1", "1"}, "67108864\n", nil, false, 0},
+		{"kills while running", []string{"1", "3", "3", "4"}, "67108864\n", []kill{{2, 64 << 20}, {1, 64 << 20}}, true, 64 << 20},
+		{"kill at exit", []string{"1", "1", "2"}, "67108864\n", []kill{{1, 64 << 20}}, true, 64 << 20},
+		{"no limit", []string{"1", "2"}, "max\n", []kill{{1, 0}}, true, 0},
+	}
+	for _, tt := range tests {
+		fakeCgroup(t, "c", memoryEvents(tt.events[0]), tt.max)
+		var calls []kill
+		w := newTestWatch(t, "c", &calls)
+		last := len(tt.events) - 1
+		for _, kills := range tt.events[1:last] {
+			setCgroup(t, "c", memoryEvents(kills), tt.max)
+			w.poll()
+		}
+		setCgroup(t, "c", memoryEvents(tt.events[last]), tt.max)
+		killed, limit := w.check()
+		if killed != tt.killed || limit != tt.limit {
+			t.Errorf("%s: check = %v, %d, want %v, %d", tt.name, killed, limit, tt.killed, tt.limit)
+		}
+		if len(calls) != len(tt.want) {
+			t.Errorf("%s: onKill calls = %v, want %v", tt.name, calls, tt.want)
+			continue
+		}
+		for i := range calls {
+			if calls[i] != tt.want[i] {
+				t.Errorf("%s: onKill calls = %v, want %v", tt.name, calls, tt.want)
+				break
+			}
+		}
+		// check stopped the watch, closing it again is harmless
+		w.close()
+	}
+}
+
+func TestOOMWatchCgroupGone(t *testing.T) {
+	fakeCgroup(t, "c", memoryEvents("0"), "67108864\n")
+	var calls []kill
+	w := newTestWatch(t, "c", &calls)
+	setCgroup(t, "c", memoryEvents("1"), "67108864\n")
+	w.poll()
+	// the kill seen before runc deleted the cgroup still counts
+	if err := os.RemoveAll(filepath.Join(cgroupRoot, "c")); err != nil {
+		t.Fatal(err)
+	}
+	if killed, _ := w.check(); !killed {
+		t.Error("check after the cgroup is gone lost the kill")
+	}
+	if len(calls) != 1 {
+		t.Errorf("onKill calls = %v, want one", calls)
+	}
+
+	// without cgroup v2 there is no watch
+	var none *oomWatch
+	if killed, limit := none.check(); killed || limit != 0 {
+		t.Errorf("check of a nil watch = %v, %d", killed, limit)
+	}
+	none.close()
+}
+
+// testEvents is an event forwarder whose events are left in its queue
+func testEvents() *eventForwarder {
+	return &eventForwarder{queue: make(chan *task.TaskEvent, 16)}
+}
+
+func TestRecordExitOOM(t *testing.T) {
+	tests := []struct {
+		name   string
+		kills  string
+		reason string
+	}{
+		{"killed", "1", reasonOOMKilled},
+		{"not killed", "0", ""},
+	}
+	for _, tt := range tests {
+		fakeCgroup(t, "c", memoryEvents("0"), "67108864\n")
+		svc := runningShim()
+		svc.id = "c"
+		svc.events = testEvents()
+		var calls []kill
+		svc.oom = newTestWatch(t, "c", &calls)
+		setCgroup(t, "c", memoryEvents(tt.kills), "67108864\n")
+
+		if _, restart := svc.recordExit(137); restart {
+			t.Errorf("%s: container without a restart policy restarted", tt.name)
+		}
+		if svc.restarts.lastExitReason != tt.reason {
+			t.Errorf("%s: exit reason = %q, want %q", tt.name, svc.restarts.lastExitReason, tt.reason)
+		}
+		event := <-svc.events.queue
+		if event.Type != eventExit || event.Attributes["reason"] != tt.reason || event.Attributes["exit_status"] != "137" {
+			t.Errorf("%s: exit event = %v", tt.name, event)
+		}
+		state, err := svc.State(context.Background(), &task.StateRequest{Id: "c"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		killed := tt.reason != ""
+		if state.OomKilled != killed || (killed && state.MemoryLimit != 64<<20) {
+			t.Errorf("%s: state = %+v", tt.name, state)
+		}
+	}
+}
+
+func TestPublishOOM(t *testing.T) {
+	svc := runningShim()
+	svc.id = "c"
+	svc.events = testEvents()
+	svc.publishOOM(2, 64<<20)
+	event := <-svc.events.queue
+	if event.Type != eventOOM || event.Id != "c" {
+		t.Fatalf("event = %v", event)
+	}
+	if event.Attributes["oom_kills"] != "2" || event.Attributes["memory_limit"] != "67108864" {
+		t.Errorf("attributes = %v", event.Attributes)
+	}
+}
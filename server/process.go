@@ -0,0 +1,679 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	task "kettle/api/shim"
+	"kettle/pkg/logs"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/console"
+	"github.com/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// processIO owns the stdio of a process started by the shim and relays its
+// output to the container log, if any, and to every attached client
+type processIO struct {
+	mu       sync.Mutex
+	terminal bool
+	logs     *logs.Writer
+	stdin    io.WriteCloser
+	console  console.Console
+	clients  map[int]func(*task.AttachResponse) error
+	nextID   int
+	// done is closed once all output of the process was relayed
+	done chan struct{}
+
+	// the ends of the pipes handed to runc, closed once it started the process
+	childIn, childOut, childErr *os.File
+	relays                      sync.WaitGroup
+}
+
+func newProcessIO(terminal, stdin bool, logw *logs.Writer) (*processIO, error) {
+	pio := &processIO{
+		terminal: terminal,
+		logs:     logw,
+		clients:  map[int]func(*task.AttachResponse) error{},
+		done:     make(chan struct{}),
+	}
+	if terminal {
+		// the console only exists once runc created the process
+		return pio, nil
+	}
+	var err error
+	var stdoutR, stderrR *os.File
+	if stdin {
+		var stdinR *os.File
+		if stdinR, pio.stdin, err = os.Pipe(); err != nil {
+			return nil, err
+		}
+		pio.childIn = stdinR
+	}
+	if stdoutR, pio.childOut, err = os.Pipe(); err != nil {
+		pio.Close()
+		return nil, err
+	}
+	if stderrR, pio.childErr, err = os.Pipe(); err != nil {
+		stdoutR.Close()
+		pio.Close()
+		return nil, err
+	}
+	pio.relay(stdoutR, logs.Stdout)
+	pio.relay(stderrR, logs.Stderr)
+	go func() {
+		pio.relays.Wait()
+		close(pio.done)
+	}()
+	return pio, nil
+}
+
+// setConsole hands the pty master received from runc to the process IO
+func (pio *processIO) setConsole(c console.Console) {
+	pio.mu.Lock()
+	pio.console = c
+	pio.stdin = c
+	pio.mu.Unlock()
+	// a terminal merges stderr into stdout
+	pio.relay(c, logs.Stdout)
+	go func() {
+		pio.relays.Wait()
+		close(pio.done)
+	}()
+}
+
+// relay copies r, the process's stream, to the log and the attached clients
+// until it hits EOF. A pty master reports EIO instead once the process on the
+// other end is gone.
+func (pio *processIO) relay(r io.ReadCloser, stream string) {
+	var logw *io.PipeWriter
+	if pio.logs != nil {
+		var logr *io.PipeReader
+		logr, logw = io.Pipe()
+		pio.relays.Add(1)
+		go func() {
+			defer pio.relays.Done()
+			if err := pio.logs.Copy(stream, logr); err != nil {
+				log.Printf("failed to log %s: %v", stream, err)
+			}
+			// keep the relay going should the log fail
+			io.Copy(io.Discard, logr)
+		}()
+	}
+
+	pio.relays.Add(1)
+	go func() {
+		defer pio.relays.Done()
+		defer r.Close()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				data := append([]byte(nil), buf[:n]...)
+				if logw != nil {
+					logw.Write(data)
+				}
+				resp := &task.AttachResponse{Stdout: data}
+				if stream == logs.Stderr {
+					resp = &task.AttachResponse{Stderr: data}
+				}
+				pio.broadcast(resp)
+			}
+			if err != nil {
+				if logw != nil {
+					logw.Close()
+				}
+				return
+			}
+		}
+	}()
+}
+
+func (pio *processIO) broadcast(resp *task.AttachResponse) {
+	pio.mu.Lock()
+	defer pio.mu.Unlock()
+	for id, send := range pio.clients {
+		if err := send(resp); err != nil {
+			delete(pio.clients, id)
+		}
+	}
+}
+
+// attach registers send to receive the process output and returns a function
+// detaching it again
+func (pio *processIO) attach(send func(*task.AttachResponse) error) func() {
+	pio.mu.Lock()
+	defer pio.mu.Unlock()
+	id := pio.nextID
+	pio.nextID++
+	pio.clients[id] = send
+	return func() {
+		pio.mu.Lock()
+		delete(pio.clients, id)
+		pio.mu.Unlock()
+	}
+}
+
+func (pio *processIO) writeStdin(data []byte) error {
+	pio.mu.Lock()
+	stdin := pio.stdin
+	pio.mu.Unlock()
+	if stdin == nil {
+		return nil
+	}
+	_, err := stdin.Write(data)
+	return err
+}
+
+func (pio *processIO) closeStdin() error {
+	pio.mu.Lock()
+	defer pio.mu.Unlock()
+	if pio.stdin == nil || pio.terminal {
+		// a terminal has no separate stdin to close
+		return nil
+	}
+	err := pio.stdin.Close()
+	pio.stdin = nil
+	return err
+}
+
+func (pio *processIO) resize(width, height uint32) error {
+	pio.mu.Lock()
+	defer pio.mu.Unlock()
+	if pio.console == nil {
+		return errors.New("process has no terminal")
+	}
+	return pio.console.Resize(console.WinSize{Width: uint16(width), Height: uint16(height)})
+}
+
+// closeChild closes our copies of the pipe ends the process inherited
+func (pio *processIO) closeChild() {
+	for _, f := range []*os.File{pio.childIn, pio.childOut, pio.childErr} {
+		if f != nil {
+			f.Close()
+		}
+	}
+	pio.childIn, pio.childOut, pio.childErr = nil, nil, nil
+}
+
+func (pio *processIO) Close() {
+	pio.closeChild()
+	pio.mu.Lock()
+	defer pio.mu.Unlock()
+	if pio.stdin != nil {
+		pio.stdin.Close()
+	}
+}
+
+// consoleSocket is the socket runc sends the pty master of a terminal process to
+type consoleSocket struct {
+	path     string
+	listener *net.UnixListener
+}
+
+func newConsoleSocket(path string) (*consoleSocket, error) {
+	os.Remove(path)
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create console socket: %w", err)
+	}
+	return &consoleSocket{path: path, listener: l}, nil
+}
+
+// receive waits for runc to connect and send the pty master over SCM_RIGHTS
+func (cs *consoleSocket) receive(timeout time.Duration) (console.Console, error) {
+	cs.listener.SetDeadline(time.Now().Add(timeout))
+	conn, err := cs.listener.AcceptUnix()
+	if err != nil {
+		return nil, fmt.Errorf("runc did not send a console: %w", err)
+	}
+	defer conn.Close()
+
+	name := make([]byte, 4096)
+	oob := make([]byte, unix.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(name, oob)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		return nil, fmt.Errorf("unexpected console socket message: %v", err)
+	}
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		return nil, fmt.Errorf("unexpected console socket rights: %v", err)
+	}
+	return console.ConsoleFromFile(os.NewFile(uintptr(fds[0]), string(name[:n])))
+}
+
+func (cs *consoleSocket) Close() error {
+	err := cs.listener.Close()
+	os.Remove(cs.path)
+	return err
+}
+
+// execProcess is an additional process started in the container with runc exec
+type execProcess struct {
+	id    string
+	spec  *task.ProcessSpec
+	io    *processIO
+	pid   int
+	state taskState
+
+	exitStatus uint32
+	exitedAt   time.Time
+	exited     chan struct{}
+}
+
+func (p *execProcess) kill(sig syscall.Signal) error {
+	if p.state != stateRunning {
+		return fmt.Errorf("cannot signal exec %s, it is %s: %w", p.id, p.state, errdefs.ErrFailedPrecondition)
+	}
+	return unix.Kill(p.pid, sig)
+}
+
+// execSpec builds the runc process for an exec from the container's own
+// process, so that exec'd processes inherit its environment, capabilities and
+// security settings
+func execSpec(bundle string, ps *task.ProcessSpec) (*specs.Process, error) {
+	spec, err := readBundleSpec(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Process == nil {
+		return nil, errors.New("bundle spec has no process")
+	}
+	p := *spec.Process
+	p.Args = ps.Args
+	p.Env = append(append([]string(nil), p.Env...), ps.Env...)
+	p.Terminal = ps.Terminal
+	p.ConsoleSize = nil
+	if ps.Cwd != "" {
+		p.Cwd = ps.Cwd
+	}
+	if ps.User != "" {
+		// names and the groups of a user come from the container's own
+		// passwd and group files
+		rootfs, err := bundleRootfs(bundle)
+		if err != nil {
+			return nil, err
+		}
+		if err := oci.WithRootfsUser(ps.User, rootfs)(&specs.Spec{Process: &p}); err != nil {
+			return nil, fmt.Errorf("%v: %w", err, errdefs.ErrInvalidArgument)
+		}
+	}
+	return &p, nil
+}
+
+func newExecID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *TaskServiceImpl) Exec(ctx context.Context, req *task.ExecProcessRequest) (*task.ExecProcessResponse, error) {
+	log.Printf("Received Exec request for ID: %s\n", req.Id)
+	if req.Process == nil || len(req.Process.Args) == 0 {
+		return nil, fmt.Errorf("exec needs a command to run: %w", errdefs.ErrInvalidArgument)
+	}
+	execID := req.ExecId
+	if execID == "" {
+		execID = newExecID()
+	}
+	if !validID.MatchString(execID) {
+		return nil, fmt.Errorf("invalid exec id %q: %w", execID, errdefs.ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stateRunning {
+		return nil, fmt.Errorf("cannot exec in container %s, it is %s: %w", s.id, s.state, errdefs.ErrFailedPrecondition)
+	}
+	if _, ok := s.execs[execID]; ok {
+		return nil, fmt.Errorf("exec %s: %w", execID, errdefs.ErrAlreadyExists)
+	}
+	spec, err := execSpec(s.bundle, req.Process)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(s.execPath(execID, "json"), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write exec process: %w", err)
+	}
+	pio, err := newProcessIO(req.Process.Terminal, req.Stdin, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec io: %w", err)
+	}
+	s.execs[execID] = &execProcess{
+		id:     execID,
+		spec:   req.Process,
+		io:     pio,
+		state:  stateCreated,
+		exited: make(chan struct{}),
+	}
+	return &task.ExecProcessResponse{ExecId: execID}, nil
+}
+
+// execPath is where the runc files of an exec live, next to the shim's socket
+func (s *TaskServiceImpl) execPath(execID, ext string) string {
+	return filepath.Join(shimStateDir, s.id, "exec-"+execID+"."+ext)
+}
+
+// startExec runs an exec process with runc. Must be called with s.mu held.
+func (s *TaskServiceImpl) startExec(execID string) (*task.StartResponse, error) {
+	p, ok := s.execs[execID]
+	if !ok {
+		return nil, fmt.Errorf("exec %s: %w", execID, errdefs.ErrNotFound)
+	}
+	if err := checkTransition("exec "+execID, p.state, stateRunning, "start"); err != nil {
+		return nil, err
+	}
+	pidFile := s.execPath(execID, "pid")
+	args := []string{"exec", "--detach", "--pid-file", pidFile, "--process", s.execPath(execID, "json")}
+	var socket *consoleSocket
+	if p.spec.Terminal {
+		var err error
+		if socket, err = newConsoleSocket(s.execPath(execID, "sock")); err != nil {
+			return nil, err
+		}
+		defer socket.Close()
+		args = append(args, "--console-socket", socket.path)
+	}
+	args = append(args, s.id)
+
+	cmdExec := exec.Command("runc", args...)
+	cmdExec.Stdin = p.io.childIn
+	cmdExec.Stdout = p.io.childOut
+	cmdExec.Stderr = p.io.childErr
+	if p.spec.Terminal {
+		cmdExec.Stdout = os.Stdout
+		cmdExec.Stderr = os.Stderr
+	}
+	err := cmdExec.Run()
+	p.io.closeChild()
+	defer os.Remove(s.execPath(execID, "json"))
+	defer os.Remove(pidFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec in container: %w", err)
+	}
+	if socket != nil {
+		c, err := socket.receive(5 * time.Second)
+		if err != nil {
+			return nil, err
+		}
+		p.io.setConsole(c)
+	}
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exec pid: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec pid file: %w", err)
+	}
+	p.pid = pid
+	p.state = stateRunning
+	s.events.publish(eventStart, s.id, map[string]string{
+		"exec_id": execID,
+		"pid":     strconv.Itoa(pid),
+	})
+	go s.waitExec(p)
+	return &task.StartResponse{Pid: uint32(pid)}, nil
+}
+
+// execRetention is how long the exit of an exec process nobody waits for is
+// kept around for State and a late Wait
+const execRetention = 5 * time.Minute
+
+// waitExec reaps an exec process and records its exit
+func (s *TaskServiceImpl) waitExec(p *execProcess) {
+	status := waitPid(p.pid)
+	// let attached clients see all of the output before they see the exit,
+	// unless a child that outlived the process holds on to it
+	select {
+	case <-p.io.done:
+	case <-time.After(2 * time.Second):
+	}
+	s.mu.Lock()
+	p.exitStatus = status
+	p.exitedAt = time.Now()
+	p.state = stateStopped
+	s.mu.Unlock()
+	close(p.exited)
+	log.Printf("exec %s in container %s exited with status %d", p.id, s.id, status)
+	s.events.publish(eventExit, s.id, map[string]string{
+		"exec_id":     p.id,
+		"exit_status": strconv.Itoa(int(status)),
+	})
+	time.AfterFunc(execRetention, func() { s.removeExec(p) })
+}
+
+// removeExec forgets an exited exec process and releases its stdio. The ID
+// may have been reused already, only p itself is removed.
+func (s *TaskServiceImpl) removeExec(p *execProcess) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.execs[p.id] != p {
+		return
+	}
+	delete(s.execs, p.id)
+	p.io.Close()
+}
+
+// lookupExec returns the exec process with the given ID
+func (s *TaskServiceImpl) lookupExec(execID string) (*execProcess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if execID == "" {
+		return nil, fmt.Errorf("only exec processes are supported: %w", errdefs.ErrNotImplemented)
+	}
+	p, ok := s.execs[execID]
+	if !ok {
+		return nil, fmt.Errorf("exec %s: %w", execID, errdefs.ErrNotFound)
+	}
+	return p, nil
+}
+
+// lookupIO returns the stdio of the exec process with the given ID, or of the
+// init process if execID is empty
+func (s *TaskServiceImpl) lookupIO(execID string) (*processIO, error) {
+	if execID != "" {
+		p, err := s.lookupExec(execID)
+		if err != nil {
+			return nil, err
+		}
+		return p.io, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.io == nil {
+		return nil, fmt.Errorf("container %s has not been created: %w", s.id, errdefs.ErrFailedPrecondition)
+	}
+	return s.io, nil
+}
+
+// Wait blocks until a process exits. For the init process that is once it
+// exited without the restart policy bringing it back. An exec process is
+// removed once its exit has been delivered.
+func (s *TaskServiceImpl) Wait(ctx context.Context, req *task.WaitRequest) (*task.WaitResponse, error) {
+	if req.ExecId == "" {
+		return s.waitInit(ctx)
+	}
+	p, err := s.lookupExec(req.ExecId)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-p.exited:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	s.removeExec(p)
+	return &task.WaitResponse{
+		ExitStatus: p.exitStatus,
+		ExitedAt:   timestamppb.New(p.exitedAt),
+	}, nil
+}
+
+func (s *TaskServiceImpl) waitInit(ctx context.Context) (*task.WaitResponse, error) {
+	s.mu.Lock()
+	exited := s.exited
+	s.mu.Unlock()
+	select {
+	case <-exited:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &task.WaitResponse{ExitStatus: s.restarts.lastExitStatus}
+	if !s.restarts.lastExitAt.IsZero() {
+		resp.ExitedAt = timestamppb.New(s.restarts.lastExitAt)
+	}
+	return resp, nil
+}
+
+func (s *TaskServiceImpl) Kill(ctx context.Context, req *task.KillRequest) (*emptypb.Empty, error) {
+	log.Printf("Received Kill request for ID: %s\n", req.Id)
+	if req.ExecId == "" {
+		return s.killInit(syscall.Signal(req.Signal), req.All)
+	}
+	if req.All {
+		return nil, fmt.Errorf("all only applies to the init process: %w", errdefs.ErrInvalidArgument)
+	}
+	p, err := s.lookupExec(req.ExecId)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := p.kill(syscall.Signal(req.Signal)); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// killInit signals the container through runc, which also knows how to reach
+// every process in it
+func (s *TaskServiceImpl) killInit(sig syscall.Signal, all bool) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	id, state, pid := s.id, s.state, s.pid
+	if state == stateCreated && !s.reaping {
+		// the signal may well end a container that was never started
+		s.reaping = true
+		go s.reapCreated(pid)
+	}
+	s.mu.Unlock()
+	if state == stateUnknown || state == stateStopped {
+		return nil, fmt.Errorf("cannot signal container %s, it is %s: %w", id, state, errdefs.ErrFailedPrecondition)
+	}
+	args := []string{"kill"}
+	if all {
+		args = append(args, "--all")
+	}
+	cmdKill := exec.Command("runc", append(args, id, strconv.Itoa(int(sig)))...)
+	cmdKill.Stdout = os.Stdout
+	cmdKill.Stderr = os.Stderr
+	if err := cmdKill.Run(); err != nil {
+		return nil, fmt.Errorf("failed to signal container: %w", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServiceImpl) ResizePty(ctx context.Context, req *task.ResizePtyRequest) (*emptypb.Empty, error) {
+	pio, err := s.lookupIO(req.ExecId)
+	if err != nil {
+		return nil, err
+	}
+	if err := pio.resize(req.Width, req.Height); err != nil {
+		return nil, fmt.Errorf("%w: %w", err, errdefs.ErrFailedPrecondition)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Attach streams a process's output to the client and its stdin to the
+// process. The first request names the process and is answered with an empty
+// response once the client is attached, so that output produced after that is
+// not lost. The stream ends once the process closed its output.
+func (s *TaskServiceImpl) Attach(ctx context.Context, stream task.Task_AttachServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	pio, err := s.lookupIO(first.ExecId)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&task.AttachResponse{}); err != nil {
+		return err
+	}
+	detach := pio.attach(stream.Send)
+	defer detach()
+
+	input := make(chan error, 1)
+	go func() {
+		input <- pumpStdin(pio, first, stream.Recv)
+	}()
+	for {
+		select {
+		case <-pio.done:
+			return nil
+		case err := <-input:
+			if err != nil {
+				// the client went away, the process keeps running without it
+				return err
+			}
+			// the client is done sending but still wants the output
+			input = nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pumpStdin writes the stdin of attach requests to the process until the
+// client closes the stream
+func pumpStdin(pio *processIO, req *task.AttachRequest, recv func() (*task.AttachRequest, error)) error {
+	for {
+		if len(req.Stdin) > 0 {
+			if err := pio.writeStdin(req.Stdin); err != nil {
+				return err
+			}
+		}
+		if req.CloseStdin {
+			if err := pio.closeStdin(); err != nil {
+				return err
+			}
+		}
+		var err error
+		if req, err = recv(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
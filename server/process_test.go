@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	task "kettle/api/shim"
+	"kettle/pkg/oci"
+
+	"github.com/containerd/errdefs"
+)
+
+// newExecBundle writes a bundle whose container runs as 1000:100 with the
+// given passwd and group files in its rootfs
+func newExecBundle(t *testing.T, passwd, group string) string {
+	t.Helper()
+	bundle := t.TempDir()
+	spec := oci.DefaultSpec()
+	spec.Process.User.UID, spec.Process.User.GID = 1000, 100
+	spec.Process.Env = []string{"PATH=/bin"}
+	if err := writeBundleSpec(bundle, spec); err != nil {
+		t.Fatal(err)
+	}
+	etc := filepath.Join(bundle, spec.Root.Path, "etc")
+	if err := os.MkdirAll(etc, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"passwd": passwd, "group": group} {
+		if data == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(etc, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return bundle
+}
+
+func TestExecSpecUser(t *testing.T) {
+	bundle := newExecBundle(t,
+		"root:x:0:0::/root:/bin/sh\napp:x:1000:100::/home/app:/bin/sh\nnobody:x:65534:65534::/:/bin/false\n",
+		"root:x:0:\nusers:x:100:\nwheel:x:10:app\nnogroup:x:65534:\n")
+	tests := []struct {
+		user       string
+		uid, gid   uint32
+		additional []uint32
+	}{
+		// the container's own user
+		{"", 1000, 100, nil},
+		{"nobody", 65534, 65534, nil},
+		{"app", 1000, 100, []uint32{10}},
+		// a uid takes its group from passwd
+		{"1000", 1000, 100, []uint32{10}},
+		{"0", 0, 0, nil},
+		{"app:wheel", 1000, 10, nil},
+		{"nobody:100", 65534, 100, nil},
+		// a uid passwd does not know runs as group 0
+		{"4242", 4242, 0, nil},
+		{"4242:4242", 4242, 4242, nil},
+	}
+	for _, tt := range tests {
+		p, err := execSpec(bundle, &task.ProcessSpec{Args: []string{"id"}, User: tt.user, Env: []string{"A=1"}})
+		if err != nil {
+			t.Errorf("execSpec as %q: %v", tt.user, err)
+			continue
+		}
+		if u := p.User; u.UID != tt.uid || u.GID != tt.gid || !slices.Equal(u.AdditionalGids, tt.additional) {
+			t.Errorf("execSpec as %q runs as %d:%d %v, want %d:%d %v", tt.user, u.UID, u.GID, u.AdditionalGids, tt.uid, tt.gid, tt.additional)
+		}
+		if !slices.Equal(p.Env, []string{"PATH=/bin", "A=1"}) {
+			t.Errorf("execSpec as %q has env %q", tt.user, p.Env)
+		}
+	}
+
+	for _, user := range []string{"ghost", "app:ghosts", "-1"} {
+		if _, err := execSpec(bundle, &task.ProcessSpec{Args: []string{"id"}, User: user}); !errdefs.IsInvalidArgument(err) {
+			t.Errorf("execSpec as %q = %v, want invalid argument", user, err)
+		}
+	}
+}
+
+func TestExecSpecUserWithoutPasswd(t *testing.T) {
+	// a rootfs without passwd and group files only takes numeric IDs
+	bundle := newExecBundle(t, "", "")
+	p, err := execSpec(bundle, &task.ProcessSpec{Args: []string{"id"}, User: "1000:1000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.User.UID != 1000 || p.User.GID != 1000 {
+		t.Errorf("runs as %d:%d, want 1000:1000", p.User.UID, p.User.GID)
+	}
+	if _, err := execSpec(bundle, &task.ProcessSpec{Args: []string{"id"}, User: "app"}); !errdefs.IsInvalidArgument(err) {
+		t.Errorf("execSpec by name = %v, want invalid argument", err)
+	}
+}
+
+func TestKillState(t *testing.T) {
+	// a container that is not there or already down has nothing to signal,
+	// whether the signal is for its init process or for all of them
+	for _, state := range []taskState{stateUnknown, stateStopped} {
+		for _, all := range []bool{false, true} {
+			svc := newTaskService()
+			svc.id = "c"
+			svc.state = state
+			_, err := svc.Kill(context.Background(), &task.KillRequest{Id: "c", Signal: 9, All: all})
+			if !errdefs.IsFailedPrecondition(err) {
+				t.Errorf("Kill of a %s container with all=%v = %v, want a failed precondition", state, all, err)
+			}
+			if svc.reaping {
+				t.Errorf("Kill of a %s container reaps it", state)
+			}
+		}
+	}
+
+	// all only applies to the init process
+	svc := runningShim()
+	if _, err := svc.Kill(context.Background(), &task.KillRequest{Id: "c", ExecId: "e1", Signal: 9, All: true}); !errdefs.IsInvalidArgument(err) {
+		t.Errorf("Kill of an exec with all = %v, want invalid argument", err)
+	}
+	if _, err := svc.Kill(context.Background(), &task.KillRequest{Id: "c", ExecId: "e1", Signal: 9}); !errdefs.IsNotFound(err) {
+		t.Errorf("Kill of a missing exec = %v, want not found", err)
+	}
+}
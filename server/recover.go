@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	containerTask "kettle/api/kettle"
+	shimTask "kettle/api/shim"
+
+	"github.com/containerd/errdefs"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recoverContainers runs once at daemon startup. Shims outlive the daemon, so
+// every container that has a record or a shim state directory is checked: if
+// its shim still answers the container is adopted again, otherwise it is
+// marked exited and whatever the dead shim left behind is cleaned up.
+func (s *ContainerTaskServiceImpl) recoverContainers() {
+	ids := map[string]bool{}
+	for _, c := range s.store.List(nil) {
+		ids[c.ID] = true
+	}
+	if entries, err := os.ReadDir(shimStateDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				ids[entry.Name()] = true
+			}
+		}
+	}
+
+	for id := range ids {
+		_, lookupErr := s.store.Get(id)
+		known := lookupErr == nil
+		resp, err := probeShim(id)
+		switch {
+		case err == nil && known:
+			// the init process may have exited while nobody was watching
+			if resp.TaskPid == 0 {
+				if _, err := s.markExited(id); err != nil {
+					fmt.Println("failed to mark container exited:", id, err)
+				}
+			} else if container, err := s.store.Get(id); err == nil {
+				if err := s.checkNetwork(context.Background(), container); err != nil {
+					fmt.Println("Network check failed for container", id, err)
+				}
+			}
+			s.watchShim(id, resp.ShimPid)
+			fmt.Printf("Recovered container %s (shim pid %d, task pid %d)\n", id, resp.ShimPid, resp.TaskPid)
+		case err == nil:
+			// a live shim we hold no record for, leave it to the operator
+			fmt.Println("Ignoring shim without container metadata:", id)
+		default:
+			fmt.Println("Shim for container", id, "is gone:", err)
+			cleanupDeadShim(id)
+			if known {
+				if _, err := s.markExited(id); err != nil {
+					fmt.Println("failed to mark container exited:", id, err)
+				}
+			}
+		}
+	}
+}
+
+// watchShim follows a shim adopted from an earlier run of the daemon. It is
+// not the daemon's child to wait for, so its exit is seen through a pidfd and
+// its exit status is unknown.
+func (s *ContainerTaskServiceImpl) watchShim(id string, pid uint32) {
+	fd, err := unix.PidfdOpen(int(pid), 0)
+	if err != nil {
+		// it died since it was probed
+		s.shimExited(id, pid, -1)
+		return
+	}
+	go func() {
+		defer unix.Close(fd)
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		for {
+			// the pidfd becomes readable once the process is gone
+			if _, err := unix.Poll(fds, -1); !errors.Is(err, unix.EINTR) {
+				break
+			}
+		}
+		s.shimExited(id, pid, -1)
+	}()
+}
+
+// shimExited publishes the exit of a container's shim, status is -1 when it is
+// unknown. The container's processes cannot outlive their shim, a container
+// that is still around is marked exited.
+func (s *ContainerTaskServiceImpl) shimExited(id string, pid uint32, status int) {
+	attributes := map[string]string{"pid": strconv.Itoa(int(pid))}
+	if status >= 0 {
+		attributes["exit_status"] = strconv.Itoa(status)
+	}
+	s.events.publish(eventShimExit, id, attributes)
+	// a shim shut down by Delete takes the record with it, and a container
+	// created again under the same ID has a new shim
+	if _, err := probeShim(id); err == nil {
+		return
+	}
+	if _, err := s.markExited(id); err != nil && !errdefs.IsNotFound(err) {
+		fmt.Println("failed to mark container exited:", id, err)
+	}
+}
+
+// probeShim dials the shim of a container and checks it still serves requests
+func probeShim(id string) (*shimTask.ConnectResponse, error) {
+	shim, err := connectShim(id)
+	if err != nil {
+		return nil, err
+	}
+	defer shim.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return shim.Connect(ctx, &shimTask.ConnectRequest{Id: id})
+}
+
+// cleanupDeadShim removes the runc state and socket directory a dead shim
+// leaves behind. The container's processes lost their parent along with the
+// shim, so they are killed rather than left unmanaged.
+func cleanupDeadShim(id string) {
+	cmdDelete := exec.Command("runc", "delete", "--force", id)
+	if out, err := cmdDelete.CombinedOutput(); err != nil {
+		fmt.Printf("runc delete for %s: %v: %s\n", id, err, out)
+	}
+	if err := os.RemoveAll(filepath.Dir(shimSocketPath(id))); err != nil {
+		fmt.Println("failed to remove shim state:", id, err)
+	}
+}
+
+func (s *ContainerTaskServiceImpl) markExited(id string) (*containerTask.Container, error) {
+	return s.store.Update(id, func(c *containerTask.Container) error {
+		if c.Status == statusExited {
+			return nil
+		}
+		c.Status = statusExited
+		c.UpdatedAt = timestamppb.Now()
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	task "kettle/api/shim"
+)
+
+// Restart policies understood by the shim
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+const (
+	initialBackoff      = 10 * time.Second
+	defaultMaxBackoff   = 5 * time.Minute
+	defaultStableWindow = 10 * time.Minute
+
+	// reasonCrashLoop is reported while a restart is being delayed, same as kubelet
+	reasonCrashLoop = "CrashLoopBackOff"
+)
+
+type restartPolicy struct {
+	name         string
+	maxBackoff   time.Duration
+	stableWindow time.Duration
+}
+
+// newRestartPolicy fills in defaults for anything the caller left out
+func newRestartPolicy(p *task.RestartPolicy) (restartPolicy, error) {
+	policy := restartPolicy{
+		name:         RestartNever,
+		maxBackoff:   defaultMaxBackoff,
+		stableWindow: defaultStableWindow,
+	}
+	if p == nil {
+		return policy, nil
+	}
+	switch p.Name {
+	case "":
+	case RestartNever, RestartOnFailure, RestartAlways:
+		policy.name = p.Name
+	default:
+		return policy, fmt.Errorf("unknown restart policy %q", p.Name)
+	}
+	if d := p.MaxBackoff.AsDuration(); d > 0 {
+		policy.maxBackoff = d
+	}
+	if d := p.StableWindow.AsDuration(); d > 0 {
+		policy.stableWindow = d
+	}
+	return policy, nil
+}
+
+func (p restartPolicy) shouldRestart(exitStatus uint32) bool {
+	switch p.name {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return exitStatus != 0
+	}
+	return false
+}
+
+// backoff is the crash-loop bookkeeping for a single container
+type backoff struct {
+	restartCount   uint32
+	lastExitStatus uint32
+	lastExitAt     time.Time
+	startedAt      time.Time
+	delay          time.Duration
+	nextRestartAt  time.Time
+
+	// lastExitReason is reasonOOMKilled when the OOM killer was involved in
+	// the last exit, lastMemoryLimit the limit in effect then
+	lastExitReason  string
+	lastMemoryLimit uint64
+	oomKills        uint32
+}
+
+// next doubles the restart delay, starting over once the container
+// managed to stay up for the policy's stable window
+func (b *backoff) next(p restartPolicy, ranFor time.Duration) time.Duration {
+	if b.delay == 0 || ranFor >= p.stableWindow {
+		b.delay = initialBackoff
+	} else {
+		b.delay *= 2
+	}
+	if b.delay > p.maxBackoff {
+		b.delay = p.maxBackoff
+	}
+	return b.delay
+}
+
+func (b *backoff) waiting() bool {
+	return !b.nextRestartAt.IsZero()
+}
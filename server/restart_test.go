@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	task "kettle/api/shim"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestBackoffNext(t *testing.T) {
+	p := restartPolicy{name: RestartAlways, maxBackoff: 50 * time.Second, stableWindow: time.Minute}
+	var b backoff
+	steps := []struct {
+		ranFor time.Duration
+		want   time.Duration
+	}{
+		{time.Second, initialBackoff},
+		{time.Second, 2 * initialBackoff},
+		{time.Second, 4 * initialBackoff},
+		// capped at the policy's maximum
+		{time.Second, 50 * time.Second},
+		{time.Second, 50 * time.Second},
+		// a run as long as the stable window starts over
+		{time.Minute, initialBackoff},
+		{59 * time.Second, 2 * initialBackoff},
+	}
+	for i, step := range steps {
+		if got := b.next(p, step.ranFor); got != step.want {
+			t.Errorf("step %d: next after running %s = %s, want %s", i, step.ranFor, got, step.want)
+		}
+	}
+}
+
+func TestShouldRestart(t *testing.T) {
+	tests := []struct {
+		policy string
+		status uint32
+		want   bool
+	}{
+		{RestartNever, 1, false},
+		{RestartOnFailure, 0, false},
+		{RestartOnFailure, 137, true},
+		{RestartAlways, 0, true},
+		{RestartAlways, 1, true},
+	}
+	for _, tt := range tests {
+		if got := (restartPolicy{name: tt.policy}).shouldRestart(tt.status); got != tt.want {
+			t.Errorf("%s policy restarts on %d = %v, want %v", tt.policy, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestNewRestartPolicy(t *testing.T) {
+	p, err := newRestartPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.name != RestartNever || p.maxBackoff != defaultMaxBackoff || p.stableWindow != defaultStableWindow {
+		t.Errorf("default policy = %+v", p)
+	}
+
+	p, err = newRestartPolicy(&task.RestartPolicy{
+		Name:       RestartOnFailure,
+		MaxBackoff: durationpb.New(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.name != RestartOnFailure || p.maxBackoff != time.Minute || p.stableWindow != defaultStableWindow {
+		t.Errorf("on-failure policy = %+v", p)
+	}
+
+	if _, err := newRestartPolicy(&task.RestartPolicy{Name: "sometimes"}); err == nil {
+		t.Error("unknown policy accepted")
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	containerTask "kettle/api/kettle"
+	"kettle/pkg/image"
+	"kettle/pkg/snapshot"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/errdefs"
+	"golang.org/x/sys/unix"
+)
+
+// Snapshotters containers' root filesystems can be kept in
+const (
+	SnapshotterOverlay = "overlayfs"
+	SnapshotterNative  = "native"
+)
+
+// newSnapshotter opens the snapshotter config asks for under
+// <Root>/snapshots/<name>. Without overlayfs support on the filesystem the
+// native snapshotter is used instead.
+func newSnapshotter(config Config) (snapshot.Snapshotter, error) {
+	root := filepath.Join(config.Root, "snapshots")
+	switch config.Snapshotter {
+	case "", SnapshotterOverlay:
+		sn, err := snapshot.NewOverlay(filepath.Join(root, SnapshotterOverlay))
+		if err == nil {
+			return sn, nil
+		}
+		fmt.Println("Falling back to the native snapshotter:", err)
+		return snapshot.NewNative(filepath.Join(root, SnapshotterNative))
+	case SnapshotterNative:
+		return snapshot.NewNative(filepath.Join(root, SnapshotterNative))
+	default:
+		return nil, fmt.Errorf("unknown snapshotter %q: %w", config.Snapshotter, errdefs.ErrInvalidArgument)
+	}
+}
+
+// mountRootfs unpacks the container's image into snapshots and mounts a
+// writable snapshot on top of them, keyed by the container ID, at the rootfs
+// of its bundle
+func (s *ContainerTaskServiceImpl) mountRootfs(ctx context.Context, container *containerTask.Container) error {
+	img, err := s.images.Get(container.Image)
+	if err != nil {
+		return err
+	}
+	rootfs, err := bundleRootfs(container.Bundle)
+	if err != nil {
+		return err
+	}
+	parent, err := s.images.Unpack(ctx, img, s.snapshots)
+	if err != nil {
+		return err
+	}
+	// the ID is free, a snapshot still holding it was left by a failed create
+	if err := s.removeRootfs(ctx, container); err != nil {
+		return err
+	}
+	mounts, err := s.snapshots.Prepare(ctx, container.ID, parent)
+	if err != nil {
+		return err
+	}
+	if err := mount.All(mounts, rootfs); err != nil {
+		s.snapshots.Remove(ctx, container.ID)
+		return fmt.Errorf("failed to mount rootfs: %w", err)
+	}
+	return nil
+}
+
+// withImageRootfs unpacks the container's image and calls fn with a read-only
+// view of its root filesystem, mounted for the duration of the call
+func (s *ContainerTaskServiceImpl) withImageRootfs(ctx context.Context, container *containerTask.Container, img image.Image, fn func(rootfs string) error) error {
+	parent, err := s.images.Unpack(ctx, img, s.snapshots)
+	if err != nil {
+		return err
+	}
+	// container IDs have no slash, the key cannot be a container's
+	key := "view/" + container.ID
+	// a view still holding the key was left by a daemon that died using it
+	s.snapshots.Remove(ctx, key)
+	mounts, err := s.snapshots.View(ctx, key, parent)
+	if err != nil {
+		return err
+	}
+	defer s.snapshots.Remove(ctx, key)
+	return mount.WithReadonlyTempMount(ctx, mounts, fn)
+}
+
+// removeRootfs unmounts the container's rootfs and removes its snapshot, a
+// container created from a bundle has none
+func (s *ContainerTaskServiceImpl) removeRootfs(ctx context.Context, container *containerTask.Container) error {
+	if container.Image == "" {
+		return nil
+	}
+	if rootfs, err := bundleRootfs(container.Bundle); err == nil {
+		if err := mount.UnmountAll(rootfs, unix.MNT_DETACH); err != nil {
+			return fmt.Errorf("failed to unmount rootfs: %w", err)
+		}
+	}
+	if err := s.snapshots.Remove(ctx, container.ID); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to remove rootfs snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/v2/plugins/snapshots/overlay/overlayutils"
+	"github.com/containerd/errdefs"
+)
+
+func TestNewSnapshotter(t *testing.T) {
+	// the default falls back to native where overlayfs cannot be used
+	fallback := SnapshotterOverlay
+	if err := overlayutils.Supported(t.TempDir()); err != nil {
+		fallback = SnapshotterNative
+	}
+	tests := []struct {
+		snapshotter string
+		want        string
+	}{
+		{"", fallback},
+		{SnapshotterOverlay, fallback},
+		{SnapshotterNative, SnapshotterNative},
+	}
+	for _, tt := range tests {
+		root := t.TempDir()
+		if _, err := newSnapshotter(Config{Root: root, Snapshotter: tt.snapshotter}); err != nil {
+			t.Errorf("newSnapshotter(%q): %v", tt.snapshotter, err)
+			continue
+		}
+		for _, name := range []string{SnapshotterOverlay, SnapshotterNative} {
+			_, err := os.Stat(filepath.Join(root, "snapshots", name, "snapshots"))
+			if opened := err == nil; opened != (name == tt.want) {
+				t.Errorf("newSnapshotter(%q) opened %s: %v, want %s", tt.snapshotter, name, opened, tt.want)
+			}
+		}
+	}
+
+	if _, err := newSnapshotter(Config{Root: t.TempDir(), Snapshotter: "btrfs"}); !errdefs.IsInvalidArgument(err) {
+		t.Errorf("newSnapshotter of an unknown snapshotter = %v, want invalid argument", err)
+	}
+}
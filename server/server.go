@@ -2,19 +2,46 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
 	containerTask "kettle/api/kettle"
 	task "kettle/api/shim"
 
+	"github.com/containerd/errdefs/pkg/errgrpc"
 	"github.com/containerd/ttrpc"
 	"google.golang.org/grpc"
 )
 
-func CreateGRPCServer(ctx context.Context) error {
+// Config holds the daemon settings
+type Config struct {
+	// Root is where the daemon keeps its persistent state
+	Root string
+	// LogMaxSize is the size in bytes at which a container log is rotated
+	LogMaxSize int64
+	// LogMaxFiles is how many rotated container logs are kept
+	LogMaxFiles int
+	// Snapshotter keeps the root filesystems of containers created from
+	// images, SnapshotterOverlay or SnapshotterNative
+	Snapshotter string
+	// CNIConfDir holds the CNI network configs, the first of which containers
+	// with a network namespace of their own are added to. Without one they
+	// only get loopback.
+	CNIConfDir string
+	// CNIBinDir is where the CNI plugin binaries are
+	CNIBinDir string
+}
+
+func CreateGRPCServer(ctx context.Context, config Config) error {
+	service, err := NewContainerTaskService(config)
+	if err != nil {
+		return fmt.Errorf("failed to load container metadata: %w", err)
+	}
+
 	socketPath := "/run/kettle/kettle.sock"
 	if err := os.MkdirAll("/run/kettle", 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
@@ -32,10 +59,19 @@ func CreateGRPCServer(ctx context.Context) error {
 		return fmt.Errorf("failed to set socket permissions: %w", err)
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(errorInterceptor),
+		grpc.StreamInterceptor(streamErrorInterceptor),
+	)
 
 	// Create and register your service
-	containerTask.RegisterContainersServer(server, &ContainerTaskServiceImpl{}) // Note: usually ends with "Server"
+	containerTask.RegisterContainersServer(server, service) // Note: usually ends with "Server"
+
+	go func() {
+		if err := serveShimEvents(ctx, service.events); err != nil {
+			fmt.Println("Shim events server stopped:", err)
+		}
+	}()
 
 	fmt.Println("gRPC server started on", socketPath)
 
@@ -52,7 +88,53 @@ func CreateGRPCServer(ctx context.Context) error {
 	return nil
 }
 
-func CreateTTRPCServer(ctx context.Context, socketPath string) error {
+// errorInterceptor maps errdefs errors onto grpc status codes so clients can tell
+// a missing container apart from a failed one
+func errorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	return resp, errgrpc.ToGRPC(err)
+}
+
+func streamErrorInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return errgrpc.ToGRPC(handler(srv, ss))
+}
+
+// serveShimEvents takes the events the shims forward on eventsSocketPath and
+// puts them on bus, until ctx is done
+func serveShimEvents(ctx context.Context, bus *eventBus) error {
+	if err := os.RemoveAll(eventsSocketPath); err != nil {
+		return fmt.Errorf("failed to remove existing socket: %w", err)
+	}
+	listener, err := net.Listen("unix", eventsSocketPath)
+	if err != nil {
+		return fmt.Errorf("failed to create socket: %w", err)
+	}
+	server, err := ttrpc.NewServer()
+	if err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to create ttrpc server: %w", err)
+	}
+	task.RegisterEventsService(server, &shimEvents{bus: bus})
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	if err := server.Serve(ctx, listener); err != nil && !errors.Is(err, ttrpc.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// shimErrorInterceptor does for the shim what errorInterceptor does for the
+// daemon, which turns the codes back into errdefs errors on its end
+func shimErrorInterceptor(ctx context.Context, unmarshal ttrpc.Unmarshaler, info *ttrpc.UnaryServerInfo, method ttrpc.Method) (any, error) {
+	resp, err := method(ctx, unmarshal)
+	return resp, errgrpc.ToGRPC(err)
+}
+
+// CreateTTRPCServer serves the shim's task service on socketPath, calling ready
+// once the socket accepts connections
+func CreateTTRPCServer(ctx context.Context, socketPath string, ready func(address string)) error {
 	socketDir := filepath.Dir(socketPath)
 	if err := os.MkdirAll(socketDir, 0755); err != nil {
 		fmt.Println("Failed to create directory:", err)
@@ -68,17 +150,39 @@ func CreateTTRPCServer(ctx context.Context, socketPath string) error {
 		os.Exit(1)
 	}
 
-	server, err := ttrpc.NewServer()
+	server, err := ttrpc.NewServer(ttrpc.WithUnaryServerInterceptor(shimErrorInterceptor))
 	if err != nil {
 		fmt.Println("Failed to create ttrpc server:", err)
 		os.Exit(1)
 	}
 	// Register your service
-	task.RegisterTaskService(server, &TaskServiceImpl{})
+	service := newTaskService()
+	task.RegisterTaskService(server, service)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-service.shutdown
+		// let the Shutdown reply go out before the connections are closed
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			server.Close()
+		}
+	}()
+	if ready != nil {
+		ready(socketPath)
+	}
 	fmt.Println(" ttrpc server started on", socketPath)
 
-	if err := server.Serve(ctx, listener); err != nil {
+	if err := server.Serve(ctx, listener); err != nil && !errors.Is(err, ttrpc.ErrServerClosed) {
 		fmt.Println("Server stopped:", err)
 	}
+	select {
+	case <-service.shutdown:
+		// Serve returns as soon as the listener is closed
+		<-stopped
+	default:
+	}
+	os.Remove(socketPath)
 	return nil
 }
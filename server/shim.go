@@ -1,60 +1,791 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	task "kettle/api/shim"
+	"kettle/pkg/logs"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/errdefs/pkg/errgrpc"
+	"github.com/containerd/ttrpc"
+	"github.com/containerd/typeurl/v2"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-type TaskServiceImpl struct{}
+// shimStateDir holds one directory per container with the shim's socket
+const shimStateDir = "/run/kettle/containers"
 
-// is run by containerd daemon to call the shim binary
-func runShim(id string) (pid uint32, err error) {
-	cmdDelete := exec.Command("kettle-shim", "start", "--id", id)
-	cmdDelete.Stdout = os.Stdout
-	cmdDelete.Stderr = os.Stderr
-	if err := cmdDelete.Run(); err != nil {
-		return 0, fmt.Errorf("failed to create container shim: %w", err)
+// shimVersion is reported by Connect so the daemon knows what it talks to
+const shimVersion = "1"
+
+// TaskServiceImpl is served by kettle-shim. Each shim owns exactly one container
+// and, being a child subreaper, becomes the parent of the container's init process.
+type TaskServiceImpl struct {
+	mu       sync.Mutex
+	id       string
+	bundle   string
+	pid      int
+	state    taskState
+	policy   restartPolicy
+	restarts backoff
+	stop     chan struct{}
+	logs     *logs.Writer
+	logPath  string
+	execs    map[string]*execProcess
+	// exited is closed once the init process is down for good, that is
+	// without a restart to follow
+	exited chan struct{}
+	// shutdown is closed to have the shim stop serving and exit
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+
+	// stdio of the init process, replaced whenever the container is restarted
+	terminal  bool
+	openStdin bool
+	io        *processIO
+
+	// oom follows the OOM kills of the current run of the init process
+	oom *oomWatch
+
+	// events carries what happens to the container to the daemon
+	events *eventForwarder
+
+	// reaping is set once a container that was never started has been
+	// signalled, its init process is then reaped by reapCreated
+	reaping bool
+}
+
+func newTaskService() *TaskServiceImpl {
+	return &TaskServiceImpl{
+		state:    stateUnknown,
+		stop:     make(chan struct{}),
+		execs:    map[string]*execProcess{},
+		exited:   make(chan struct{}),
+		shutdown: make(chan struct{}),
+		events:   newEventForwarder(),
 	}
-	return pid, nil
+}
+
+func shimSocketPath(id string) string {
+	return filepath.Join(shimStateDir, id, "shim.sock")
+}
+
+func shimPidPath(id string) string {
+	return filepath.Join(shimStateDir, id, "shim.pid")
+}
+
+// is run by containerd daemon to call the shim binary. A shim that is still
+// serving the container is reused, otherwise a new one is spawned in its own
+// session so that it outlives the daemon. The shim reports its address on
+// stdout once it is listening. The start of a shim spawned here is published
+// on events and exited is called with its exit status once it is gone.
+func runShim(id string, events *eventBus, exited func(pid uint32, status int)) (pid uint32, address string, err error) {
+	if resp, err := probeShim(id); err == nil {
+		return resp.ShimPid, shimSocketPath(id), nil
+	}
+	// a shim that has a pid but does not answer is hung, do not let it
+	// compete with the new one for the container
+	if oldPid, err := readShimPid(id); err == nil {
+		unix.Kill(oldPid, unix.SIGKILL)
+	}
+
+	dir := filepath.Dir(shimSocketPath(id))
+	if err := os.MkdirAll(dir, 0711); err != nil {
+		return 0, "", fmt.Errorf("failed to create shim state directory: %w", err)
+	}
+	os.Remove(shimSocketPath(id))
+	os.Remove(shimPidPath(id))
+	logFile, err := os.OpenFile(filepath.Join(dir, "shim.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to open shim log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmdShim := exec.Command("kettle-shim", "start", "--id", id)
+	cmdShim.Stderr = logFile
+	cmdShim.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	stdout, err := cmdShim.StdoutPipe()
+	if err != nil {
+		return 0, "", err
+	}
+	if err := cmdShim.Start(); err != nil {
+		return 0, "", fmt.Errorf("failed to create container shim: %w", err)
+	}
+	address, err = readShimAddress(stdout, 5*time.Second)
+	if err != nil {
+		cmdShim.Process.Kill()
+		cmdShim.Wait()
+		return 0, "", fmt.Errorf("shim for %s did not come up: %w", id, err)
+	}
+	pid = uint32(cmdShim.Process.Pid)
+	events.publish(eventShimStart, id, map[string]string{
+		"pid":     strconv.Itoa(int(pid)),
+		"address": address,
+	})
+	// the shim serves until its container is gone, reap it whenever that happens
+	go func() {
+		cmdShim.Wait()
+		exited(pid, cmdShim.ProcessState.ExitCode())
+	}()
+	return pid, address, nil
+}
+
+func readShimAddress(r io.Reader, timeout time.Duration) (string, error) {
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(r).ReadString('\n')
+		lines <- strings.TrimSpace(line)
+	}()
+	select {
+	case address := <-lines:
+		if address == "" {
+			return "", fmt.Errorf("shim exited without reporting its address")
+		}
+		return address, nil
+	case <-time.After(timeout):
+		return "", fmt.Errorf("timed out waiting for shim address")
+	}
+}
+
+func readShimPid(id string) (int, error) {
+	data, err := os.ReadFile(shimPidPath(id))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+type shimClient struct {
+	task.TaskClient
+	conn *ttrpc.Client
+}
+
+func (c *shimClient) Close() error {
+	return c.conn.Close()
+}
+
+// connectShim dials the ttrpc socket of the shim owning the given container
+func connectShim(id string) (*shimClient, error) {
+	conn, err := net.Dial("unix", shimSocketPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to shim for %s: %w", id, err)
+	}
+	client := ttrpc.NewClient(conn, ttrpc.WithUnaryClientInterceptor(nativeErrorInterceptor))
+	return &shimClient{TaskClient: task.NewTaskClient(client), conn: client}, nil
+}
+
+// nativeErrorInterceptor turns the status codes of shim errors back into
+// errdefs errors, so the daemon can tell a refused request from a failed one
+func nativeErrorInterceptor(ctx context.Context, req *ttrpc.Request, resp *ttrpc.Response, info *ttrpc.UnaryClientInfo, invoker ttrpc.Invoker) error {
+	return errgrpc.ToNative(invoker(ctx, req, resp))
 }
 
 // used by kettle shim to initialize itself
-func StartShim(id string) (pid uint32, err error) {
-	CreateTTRPCServer(context.TODO(), "/run/kettle/containers/+"+id+"/"+id+"ttrpc.sock")
-	return pid, nil
+func StartShim(id string) error {
+	// container init processes get reparented to us once runc exits
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to become subreaper: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(shimPidPath(id)), 0711); err != nil {
+		return fmt.Errorf("failed to create shim state directory: %w", err)
+	}
+	if err := os.WriteFile(shimPidPath(id), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return fmt.Errorf("failed to write shim pid file: %w", err)
+	}
+	// a pid file left behind could name an unrelated process later on
+	defer os.Remove(shimPidPath(id))
+	return CreateTTRPCServer(context.TODO(), shimSocketPath(id), func(address string) {
+		// stdout is the pipe the daemon reads our address from, it goes
+		// away with the daemon so everything after this goes to the log
+		fmt.Println(address)
+		unix.Dup2(int(os.Stderr.Fd()), int(os.Stdout.Fd()))
+	})
+}
+
+func (s *TaskServiceImpl) Create(ctx context.Context, req *task.CreateTaskRequest) (*task.CreateTaskResponse, error) {
+	log.Printf("Received Create request for ID: %s\n", req.Id)
+	policy, err := newRestartPolicy(req.RestartPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stateUnknown {
+		return nil, fmt.Errorf("container %s: %w", s.id, errdefs.ErrAlreadyExists)
+	}
+	// the daemon reuses a live shim when a container ID comes back
+	s.stop = make(chan struct{})
+	s.restarts = backoff{}
+	s.exited = make(chan struct{})
+	s.reaping = false
+	s.id = req.Id
+	s.bundle = req.Bundle
+	s.policy = policy
+	s.terminal = req.Terminal
+	s.openStdin = req.OpenStdin
+	if req.LogPath != "" {
+		if s.logs != nil {
+			s.logs.Close()
+		}
+		s.logs, err = logs.NewWriter(req.LogPath, req.LogMaxSize, int(req.LogMaxFiles))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open container log: %w", err)
+		}
+	}
+	s.logPath = req.LogPath
+	pid, err := s.createContainer()
+	if err != nil {
+		return nil, err
+	}
+	s.pid = pid
+	s.state = stateCreated
+	return &task.CreateTaskResponse{Pid: uint32(pid)}, nil
 }
-func (s TaskServiceImpl) Start(ctx context.Context, req *task.StartRequest) (*task.StartResponse, error) {
+
+func (s *TaskServiceImpl) Start(ctx context.Context, req *task.StartRequest) (*task.StartResponse, error) {
 	log.Printf("Received Start request for ID: %s\n", req.ContainerId)
-	startContainer(req.ContainerId)
 
-	// Call your actual function here
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.ExecId != "" {
+		return s.startExec(req.ExecId)
+	}
+	if err := checkTransition("container "+s.id, s.state, stateRunning, "start"); err != nil {
+		return nil, err
+	}
+	if s.reaping {
+		return nil, fmt.Errorf("container %s was signalled before it was started: %w", s.id, errdefs.ErrFailedPrecondition)
+	}
+	if err := startContainer(req.ContainerId); err != nil {
+		return nil, err
+	}
+	s.state = stateRunning
+	s.restarts.startedAt = time.Now()
+	s.events.publish(eventStart, s.id, map[string]string{"pid": strconv.Itoa(s.pid)})
+	go s.monitor(s.pid)
 
-	return &task.StartResponse{}, nil
+	return &task.StartResponse{Pid: uint32(s.pid)}, nil
 }
 
-func (s TaskServiceImpl) Delete(ctx context.Context, req *task.DeleteRequest) (*task.DeleteResponse, error) {
+func (s *TaskServiceImpl) Delete(ctx context.Context, req *task.DeleteRequest) (*task.DeleteResponse, error) {
 	log.Printf("Received Delete request for ID: %s\n", req.Id)
-	cmdDelete := exec.Command("runc", "delete", req.Id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == stateUnknown {
+		return nil, fmt.Errorf("container %s: %w", req.Id, errdefs.ErrNotFound)
+	}
+	from := s.state
+	if req.Force && (from == stateRunning || from == statePaused) {
+		from = stateStopped
+	}
+	if err := checkTransition("container "+s.id, from, stateUnknown, "delete"); err != nil {
+		return nil, fmt.Errorf("%w, stop it first or force the delete", err)
+	}
+
+	args := []string{"delete", req.Id}
+	if req.Force {
+		args = []string{"delete", "--force", req.Id}
+	}
+	cmdDelete := exec.Command("runc", args...)
 	cmdDelete.Stdout = os.Stdout
 	cmdDelete.Stderr = os.Stderr
 	if err := cmdDelete.Run(); err != nil {
 		return nil, fmt.Errorf("failed to delete container: %w", err)
 	}
 
-	return &task.DeleteResponse{}, nil
+	s.state = stateUnknown
+	close(s.stop)
+	s.oom.close()
+	// runc delete takes the exec processes down with the container
+	for _, p := range s.execs {
+		p.io.Close()
+	}
+	s.execs = map[string]*execProcess{}
+	if s.io != nil {
+		s.io.Close()
+	}
+	// a container deleted before it ever ran has nothing to report
+	s.markStopped()
+	if s.logs != nil {
+		s.logs.Close()
+		s.logs = nil
+	}
+	return &task.DeleteResponse{Id: req.Id}, nil
+}
+
+// Shutdown has the shim exit once its container has been deleted, the reply
+// still reaches the daemon
+func (s *TaskServiceImpl) Shutdown(ctx context.Context, req *task.ShutdownRequest) (*emptypb.Empty, error) {
+	log.Printf("Received Shutdown request for ID: %s\n", req.Id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stateUnknown {
+		return nil, fmt.Errorf("container %s still exists, delete it first: %w", s.id, errdefs.ErrFailedPrecondition)
+	}
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+	return &emptypb.Empty{}, nil
+}
+
+// Connect lets the daemon check the shim is alive and find out about its container
+func (s *TaskServiceImpl) Connect(ctx context.Context, req *task.ConnectRequest) (*task.ConnectResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &task.ConnectResponse{
+		ShimPid: uint32(os.Getpid()),
+		TaskPid: uint32(s.pid),
+		Version: shimVersion,
+	}, nil
+}
+
+// State reports where the container, or one of its exec processes, is in its lifecycle
+func (s *TaskServiceImpl) State(ctx context.Context, req *task.StateRequest) (*task.StateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.ExecId != "" {
+		p, ok := s.execs[req.ExecId]
+		if !ok {
+			return nil, fmt.Errorf("exec %s: %w", req.ExecId, errdefs.ErrNotFound)
+		}
+		resp := &task.StateResponse{
+			Id:       s.id,
+			ExecId:   p.id,
+			Bundle:   s.bundle,
+			Pid:      uint32(p.pid),
+			Status:   string(p.state),
+			Terminal: p.spec.Terminal,
+		}
+		if p.state == stateStopped {
+			resp.ExitStatus = p.exitStatus
+			resp.ExitedAt = timestamppb.New(p.exitedAt)
+		}
+		return resp, nil
+	}
+
+	resp := &task.StateResponse{
+		Id:           s.id,
+		Bundle:       s.bundle,
+		Pid:          uint32(s.pid),
+		Status:       string(s.state),
+		Stdout:       s.logPath,
+		Stderr:       s.logPath,
+		Terminal:     s.terminal,
+		ExitStatus:   s.restarts.lastExitStatus,
+		RestartCount: s.restarts.restartCount,
+		ExitReason:   s.restarts.lastExitReason,
+		OomKilled:    s.restarts.lastExitReason == reasonOOMKilled,
+		MemoryLimit:  s.restarts.lastMemoryLimit,
+	}
+	if !s.restarts.lastExitAt.IsZero() {
+		resp.ExitedAt = timestamppb.New(s.restarts.lastExitAt)
+	}
+	if !s.restarts.startedAt.IsZero() {
+		resp.StartedAt = timestamppb.New(s.restarts.startedAt)
+	}
+	return resp, nil
+}
+
+func (s *TaskServiceImpl) Update(ctx context.Context, req *task.UpdateTaskRequest) (*emptypb.Empty, error) {
+	log.Printf("Received Update request for ID: %s\n", req.Id)
+	if req.RestartPolicy != nil {
+		policy, err := newRestartPolicy(req.RestartPolicy)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		if s.state == stateUnknown {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("container %s: %w", req.Id, errdefs.ErrNotFound)
+		}
+		s.policy = policy
+		if s.restarts.waiting() && !policy.shouldRestart(s.restarts.lastExitStatus) {
+			// the pending restart is called off, the container is down for good
+			s.restarts.nextRestartAt = time.Time{}
+			s.markStopped()
+		}
+		s.mu.Unlock()
+	}
+	if req.Resources != nil {
+		if err := s.updateResources(req.Id, req.Resources); err != nil {
+			return nil, err
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// updateResources applies new cgroup limits to the container through runc. A
+// stopped container has no cgroup, the limits reach it through the bundle's
+// spec the next time it starts.
+func (s *TaskServiceImpl) updateResources(id string, resources *anypb.Any) error {
+	v, err := typeurl.UnmarshalAny(resources)
+	if err != nil {
+		return fmt.Errorf("invalid resources: %v: %w", err, errdefs.ErrInvalidArgument)
+	}
+	r, ok := v.(*specs.LinuxResources)
+	if !ok {
+		return fmt.Errorf("resources are a %T, not linux resources: %w", v, errdefs.ErrInvalidArgument)
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case stateUnknown:
+		return fmt.Errorf("container %s: %w", id, errdefs.ErrNotFound)
+	case stateStopped:
+		return nil
+	}
+	cmdUpdate := exec.Command("runc", "update", "--resources", "-", s.id)
+	cmdUpdate.Stdin = bytes.NewReader(data)
+	if out, err := cmdUpdate.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update resources: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// Pause freezes the container. runc puts all of its processes into the cgroup
+// freezer, so they stop being scheduled without noticing anything.
+func (s *TaskServiceImpl) Pause(ctx context.Context, req *task.PauseRequest) (*emptypb.Empty, error) {
+	log.Printf("Received Pause request for ID: %s\n", req.Id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := checkTransition("container "+s.id, s.state, statePaused, "pause"); err != nil {
+		return nil, err
+	}
+	cmdPause := exec.Command("runc", "pause", s.id)
+	cmdPause.Stdout = os.Stdout
+	cmdPause.Stderr = os.Stderr
+	if err := cmdPause.Run(); err != nil {
+		return nil, fmt.Errorf("failed to pause container: %w", err)
+	}
+	s.state = statePaused
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServiceImpl) Resume(ctx context.Context, req *task.ResumeRequest) (*emptypb.Empty, error) {
+	log.Printf("Received Resume request for ID: %s\n", req.Id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != statePaused {
+		return nil, fmt.Errorf("cannot resume container %s, it is %s: %w", s.id, s.state, errdefs.ErrFailedPrecondition)
+	}
+	cmdResume := exec.Command("runc", "resume", s.id)
+	cmdResume.Stdout = os.Stdout
+	cmdResume.Stderr = os.Stderr
+	if err := cmdResume.Run(); err != nil {
+		return nil, fmt.Errorf("failed to resume container: %w", err)
+	}
+	s.state = stateRunning
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServiceImpl) RestartStatus(ctx context.Context, req *task.RestartStatusRequest) (*task.RestartStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &task.RestartStatusResponse{
+		Id:             s.id,
+		Pid:            uint32(s.pid),
+		RestartCount:   s.restarts.restartCount,
+		LastExitStatus: s.restarts.lastExitStatus,
+		Backoff:        durationpb.New(s.restarts.delay),
+		OomKills:       s.restarts.oomKills,
+		LastExitReason: s.restarts.lastExitReason,
+	}
+	if !s.restarts.lastExitAt.IsZero() {
+		resp.LastExitAt = timestamppb.New(s.restarts.lastExitAt)
+	}
+	if s.restarts.waiting() {
+		resp.NextRestartAt = timestamppb.New(s.restarts.nextRestartAt)
+		resp.Reason = reasonCrashLoop
+	}
+	return resp, nil
+}
+
+// monitor reaps the container's init process and restarts it for as long as
+// the restart policy asks for it
+func (s *TaskServiceImpl) monitor(pid int) {
+	status := waitPid(pid)
+	for {
+		delay, ok := s.recordExit(status)
+		if !ok {
+			return
+		}
+		log.Printf("container %s exited with status %d, restarting in %s", s.id, status, delay)
+		select {
+		case <-time.After(delay):
+		case <-s.stop:
+			s.mu.Lock()
+			s.markStopped()
+			s.mu.Unlock()
+			return
+		}
+
+		newPid, err := s.restart()
+		if errors.Is(err, errRestartCancelled) {
+			return
+		}
+		if err != nil {
+			// a failed restart counts as another crash
+			log.Printf("failed to restart container %s: %v", s.id, err)
+			status = 255
+			continue
+		}
+		status = waitPid(newPid)
+	}
+}
+
+// recordExit books an exit of the init process and returns how long to wait
+// before restarting, or false if the container should stay down
+func (s *TaskServiceImpl) recordExit(status uint32) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.pid = 0
+	s.restarts.lastExitStatus = status
+	s.restarts.lastExitAt = now
+	if s.state == stateUnknown {
+		// deleted under our feet
+		s.restarts.nextRestartAt = time.Time{}
+		return 0, false
+	}
+	s.restarts.lastExitReason = ""
+	s.restarts.lastMemoryLimit = 0
+	if killed, limit := s.oom.check(); killed {
+		s.restarts.lastExitReason = reasonOOMKilled
+		s.restarts.lastMemoryLimit = limit
+		s.restarts.oomKills++
+		log.Printf("container %s was OOM-killed, memory limit %d", s.id, limit)
+	}
+	s.state = stateStopped
+	exit := map[string]string{"exit_status": strconv.Itoa(int(status))}
+	if s.restarts.lastExitReason != "" {
+		exit["reason"] = s.restarts.lastExitReason
+	}
+	s.events.publish(eventExit, s.id, exit)
+	if !s.policy.shouldRestart(status) {
+		s.restarts.nextRestartAt = time.Time{}
+		s.markStopped()
+		return 0, false
+	}
+	delay := s.restarts.next(s.policy, now.Sub(s.restarts.startedAt))
+	s.restarts.nextRestartAt = now.Add(delay)
+	s.events.publish(eventBackoff, s.id, map[string]string{
+		"delay":         delay.String(),
+		"restart_count": strconv.Itoa(int(s.restarts.restartCount)),
+	})
+	return delay, true
+}
+
+// publishOOM reports OOM kills in the container's cgroup as its OOM watch sees
+// them, the init process may well survive them
+func (s *TaskServiceImpl) publishOOM(kills, limit uint64) {
+	log.Printf("OOM killer hit container %s %d times, memory limit %d", s.id, kills, limit)
+	s.events.publish(eventOOM, s.id, map[string]string{
+		"oom_kills":    strconv.FormatUint(kills, 10),
+		"memory_limit": strconv.FormatUint(limit, 10),
+	})
+}
+
+// reapCreated waits for the init process of a container that was signalled
+// before it was started. Its monitor only runs once the container is started,
+// so nothing else would reap it.
+func (s *TaskServiceImpl) reapCreated(pid int) {
+	status := waitPid(pid)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.oom.close()
+	s.pid = 0
+	s.restarts.lastExitStatus = status
+	s.restarts.lastExitAt = time.Now()
+	if s.state == stateCreated {
+		s.state = stateStopped
+	}
+	s.events.publish(eventExit, s.id, map[string]string{"exit_status": strconv.Itoa(int(status))})
+	s.markStopped()
+}
+
+// errRestartCancelled is returned by restart when the container was deleted or
+// its restart policy changed while it waited to be restarted
+var errRestartCancelled = errors.New("restart cancelled")
+
+// markStopped wakes up everyone waiting for the init process. Must be called
+// with s.mu held.
+func (s *TaskServiceImpl) markStopped() {
+	select {
+	case <-s.exited:
+	default:
+		close(s.exited)
+	}
+}
+
+func (s *TaskServiceImpl) restart() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stateStopped || !s.restarts.waiting() {
+		return 0, errRestartCancelled
+	}
+
+	s.restarts.restartCount++
+	cmdDelete := exec.Command("runc", "delete", "--force", s.id)
+	cmdDelete.Stdout = os.Stdout
+	cmdDelete.Stderr = os.Stderr
+	if err := cmdDelete.Run(); err != nil {
+		return 0, fmt.Errorf("failed to delete exited container: %w", err)
+	}
+	pid, err := s.createContainer()
+	if err != nil {
+		return 0, err
+	}
+	s.state = stateCreated
+	if err := startContainer(s.id); err != nil {
+		return 0, err
+	}
+	s.pid = pid
+	s.state = stateRunning
+	s.restarts.startedAt = time.Now()
+	s.restarts.nextRestartAt = time.Time{}
+	s.events.publish(eventStart, s.id, map[string]string{
+		"pid":           strconv.Itoa(pid),
+		"restart_count": strconv.Itoa(int(s.restarts.restartCount)),
+	})
+	return pid, nil
 }
 
-func startContainer(id string) {
+// createContainer creates the container with its stdio held by the shim, so
+// that its output goes to the log and clients can attach to it. Must be called
+// with s.mu held.
+func (s *TaskServiceImpl) createContainer() (int, error) {
+	if s.io != nil {
+		// a restarted container gets fresh stdio, clients of the old one are detached
+		s.io.Close()
+		s.io = nil
+	}
+	pio, err := newProcessIO(s.terminal, s.openStdin, s.logs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create container io: %w", err)
+	}
+	var socket *consoleSocket
+	if s.terminal {
+		if socket, err = newConsoleSocket(filepath.Join(shimStateDir, s.id, "console.sock")); err != nil {
+			return 0, err
+		}
+		defer socket.Close()
+	}
+	pid, err := createContainer(s.bundle, s.id, pio, socket)
+	// runc handed the pipes straight to the container, our copies of its
+	// ends are closed so that the relays see EOF when it exits
+	pio.closeChild()
+	if err != nil {
+		pio.Close()
+		return 0, err
+	}
+	if socket != nil {
+		c, err := socket.receive(5 * time.Second)
+		if err != nil {
+			pio.Close()
+			return 0, err
+		}
+		pio.setConsole(c)
+	}
+	s.io = pio
+	s.oom.close()
+	s.oom = newOOMWatch(pid, s.publishOOM)
+	return pid, nil
+}
+
+// createContainer runs runc create and returns the pid of the container's init
+// process. With a console socket runc allocates a pty and sends its master there.
+func createContainer(bundlePath, containerID string, pio *processIO, socket *consoleSocket) (int, error) {
+	pidFile := filepath.Join(bundlePath, "init.pid")
+	args := []string{"create", "--bundle", bundlePath, "--pid-file", pidFile}
+	if socket != nil {
+		args = append(args, "--console-socket", socket.path)
+	}
+	cmdCreate := exec.Command("runc", append(args, containerID)...)
+	cmdCreate.Stdin = pio.childIn
+	cmdCreate.Stdout = pio.childOut
+	cmdCreate.Stderr = pio.childErr
+	if socket != nil {
+		cmdCreate.Stdout = os.Stdout
+		cmdCreate.Stderr = os.Stderr
+	}
+	if err := cmdCreate.Run(); err != nil {
+		return 0, fmt.Errorf("failed to create container: %w", err)
+	}
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read container pid: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid container pid file: %w", err)
+	}
+	fmt.Println("Container created:", containerID)
+	return pid, nil
+}
+
+func startContainer(id string) error {
 	cmdStart := exec.Command("runc", "start", id)
 	cmdStart.Stdout = os.Stdout
 	cmdStart.Stderr = os.Stderr
 	if err := cmdStart.Run(); err != nil {
-		return
+		return fmt.Errorf("failed to start container: %w", err)
 	}
 	fmt.Println("Started container:", id)
+	return nil
+}
+
+// waitPid blocks until pid exits and returns its exit status, using the
+// shell convention of 128+signal for processes killed by a signal
+func waitPid(pid int) uint32 {
+	var ws unix.WaitStatus
+	for {
+		_, err := unix.Wait4(pid, &ws, 0, nil)
+		if err == unix.EINTR {
+			continue
+		}
+		if err == unix.ECHILD {
+			// not our child after all, all we can do is watch for it to go away
+			for unix.Kill(pid, 0) == nil {
+				time.Sleep(time.Second)
+			}
+			return 255
+		}
+		if err != nil {
+			return 255
+		}
+		break
+	}
+	if ws.Signaled() {
+		return 128 + uint32(ws.Signal())
+	}
+	return uint32(ws.ExitStatus())
 }
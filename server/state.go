@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/containerd/errdefs"
+)
+
+// taskState is where a container or exec process is in its lifecycle
+type taskState string
+
+const (
+	// stateUnknown is a container that was never created or has been deleted
+	stateUnknown taskState = "unknown"
+	stateCreated taskState = "created"
+	stateRunning taskState = "running"
+	statePaused  taskState = "paused"
+	stateStopped taskState = "stopped"
+)
+
+// transitions lists the states each state may move on to. A running or paused
+// container is only deleted by force, which stops it on the way.
+var transitions = map[taskState][]taskState{
+	stateUnknown: {stateCreated},
+	stateCreated: {stateRunning, stateStopped, stateUnknown},
+	stateRunning: {statePaused, stateStopped},
+	statePaused:  {stateRunning, stateStopped},
+	stateStopped: {stateCreated, stateUnknown},
+}
+
+// checkTransition fails with ErrFailedPrecondition unless from may move on to
+// to, naming the action that was refused
+func checkTransition(id string, from, to taskState, action string) error {
+	if !slices.Contains(transitions[from], to) {
+		return fmt.Errorf("cannot %s %s, it is %s: %w", action, id, from, errdefs.ErrFailedPrecondition)
+	}
+	return nil
+}
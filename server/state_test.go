@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestCheckTransition(t *testing.T) {
+	allowed := []struct{ from, to taskState }{
+		{stateUnknown, stateCreated},
+		{stateCreated, stateRunning},
+		{stateCreated, stateStopped},
+		{stateCreated, stateUnknown},
+		{stateRunning, statePaused},
+		{stateRunning, stateStopped},
+		{statePaused, stateRunning},
+		{statePaused, stateStopped},
+		{stateStopped, stateCreated},
+		{stateStopped, stateUnknown},
+	}
+	for _, tt := range allowed {
+		if err := checkTransition("container c", tt.from, tt.to, "move"); err != nil {
+			t.Errorf("%s -> %s refused: %v", tt.from, tt.to, err)
+		}
+	}
+
+	refused := []struct{ from, to taskState }{
+		{stateUnknown, stateRunning},
+		{stateCreated, statePaused},
+		{stateRunning, stateCreated},
+		// running and paused containers are only deleted by force
+		{stateRunning, stateUnknown},
+		{statePaused, stateUnknown},
+		{stateStopped, stateRunning},
+		{stateStopped, statePaused},
+	}
+	for _, tt := range refused {
+		err := checkTransition("container c", tt.from, tt.to, "move")
+		if !errdefs.IsFailedPrecondition(err) {
+			t.Errorf("%s -> %s = %v, want a failed precondition", tt.from, tt.to, err)
+		}
+	}
+
+	err := checkTransition("exec e1", stateStopped, stateRunning, "start")
+	if want := "cannot start exec e1, it is stopped: failed precondition"; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	task "kettle/api/shim"
+
+	"github.com/containerd/cgroups/v3"
+	"github.com/containerd/cgroups/v3/cgroup2"
+	"github.com/containerd/errdefs"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Stats reads the counters of the cgroup the container's init process is in
+// and of the interfaces in its network namespace
+func (s *TaskServiceImpl) Stats(ctx context.Context, req *task.StatsRequest) (*task.StatsResponse, error) {
+	log.Printf("Received Stats request for ID: %s\n", req.Id)
+	s.mu.Lock()
+	pid, state := s.pid, s.state
+	s.mu.Unlock()
+	switch {
+	case state == stateUnknown:
+		return nil, fmt.Errorf("container %s: %w", req.Id, errdefs.ErrNotFound)
+	case pid == 0 || (state != stateRunning && state != statePaused):
+		return nil, fmt.Errorf("container %s is not running: %w", s.id, errdefs.ErrFailedPrecondition)
+	case cgroups.Mode() != cgroups.Unified:
+		return nil, fmt.Errorf("container stats need cgroup v2: %w", errdefs.ErrNotImplemented)
+	}
+
+	group, err := cgroup2.PidGroupPath(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cgroup of %s: %w", s.id, err)
+	}
+	cg, err := cgroup2.Load(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cgroup of %s: %w", s.id, err)
+	}
+	metrics, err := cg.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cgroup stats of %s: %w", s.id, err)
+	}
+	networks, err := readNetDev(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network stats of %s: %w", s.id, err)
+	}
+
+	resp := &task.StatsResponse{
+		Id:       s.id,
+		ReadAt:   timestamppb.Now(),
+		Networks: networks,
+	}
+	if c := metrics.CPU; c != nil {
+		resp.Cpu = &task.CPUStats{
+			UsageUsec:     c.UsageUsec,
+			UserUsec:      c.UserUsec,
+			SystemUsec:    c.SystemUsec,
+			NrPeriods:     c.NrPeriods,
+			NrThrottled:   c.NrThrottled,
+			ThrottledUsec: c.ThrottledUsec,
+		}
+	}
+	if m := metrics.Memory; m != nil {
+		resp.Memory = &task.MemoryStats{
+			Usage:     m.Usage,
+			Limit:     limitOrZero(m.UsageLimit),
+			Peak:      m.MaxUsage,
+			SwapUsage: m.SwapUsage,
+		}
+		if e := metrics.MemoryEvents; e != nil {
+			resp.Memory.OomEvents = e.Oom
+			resp.Memory.OomKillEvents = e.OomKill
+		}
+	}
+	if p := metrics.Pids; p != nil {
+		resp.Pids = &task.PidsStats{Current: p.Current, Limit: limitOrZero(p.Limit)}
+	}
+	if io := metrics.Io; io != nil {
+		resp.Io = &task.IOStats{}
+		for _, dev := range io.Usage {
+			resp.Io.ReadBytes += dev.Rbytes
+			resp.Io.WriteBytes += dev.Wbytes
+			resp.Io.ReadOps += dev.Rios
+			resp.Io.WriteOps += dev.Wios
+		}
+	}
+	return resp, nil
+}
+
+// limitOrZero maps the "max" of a cgroup limit file, read as the largest
+// uint64, to 0
+func limitOrZero(v uint64) uint64 {
+	if v == math.MaxUint64 {
+		return 0
+	}
+	return v
+}
+
+// readNetDev parses /proc/<pid>/net/dev, which lists the interfaces of the
+// network namespace pid is in
+func readNetDev(pid int) ([]*task.NetworkStats, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/net/dev", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var networks []*task.NetworkStats
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// the first two lines are headers, interface lines hold a colon
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		fields := strings.Fields(counters)
+		if name == "lo" || len(fields) < 16 {
+			continue
+		}
+		var v [16]uint64
+		for i := range v {
+			if v[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid counter for %s: %w", name, err)
+			}
+		}
+		networks = append(networks, &task.NetworkStats{
+			Name:      name,
+			RxBytes:   v[0],
+			RxPackets: v[1],
+			RxErrors:  v[2],
+			RxDropped: v[3],
+			TxBytes:   v[8],
+			TxPackets: v[9],
+			TxErrors:  v[10],
+			TxDropped: v[11],
+		})
+	}
+	return networks, scanner.Err()
+}